@@ -0,0 +1,439 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	slogcommon "github.com/samber/slog-common"
+	"github.com/samber/slog-multi/internal/slogattr"
+)
+
+// Clock abstracts time for time-based middlewares, so that tests can use a fake clock.
+type Clock interface {
+	Now() time.Time
+	// AfterFunc calls f after d and returns a function stopping the timer.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// AggregateOption configures the Aggregate middleware.
+type AggregateOption struct {
+	// Window is the duration of an aggregation window. Default: 1 minute.
+	Window time.Duration
+	// Predicate selects the aggregated records. Other records are forwarded
+	// unchanged. Default: every record is aggregated.
+	Predicate func(ctx context.Context, r slog.Record) bool
+	// GroupBy are the dotted paths of the attributes grouping records, in
+	// addition to the message. Attributes added with WithAttrs are looked up too.
+	GroupBy []string
+	// Value is the dotted path of a numeric attribute (int, uint, float or
+	// duration) summarized with sum, min, max and percentiles. Summaries are
+	// rendered as durations when the first value of the group is a duration.
+	Value string
+	// Percentiles of Value, between 0 and 1. Default: 0.5, 0.9 and 0.99.
+	Percentiles []float64
+	// MaxSamples is the number of values kept per group to compute percentiles.
+	// Beyond, values are sampled uniformly (reservoir sampling): count, sum, min
+	// and max stay exact, percentiles are estimated. Default: 1000.
+	MaxSamples int
+	// SummaryKey is the group of the summary attributes. Default: "aggregate".
+	SummaryKey string
+	// Clock is the source of time. Default: the system clock.
+	Clock Clock
+}
+
+// Aggregate creates a middleware turning high-frequency records into periodic
+// summaries. Aggregated records are grouped by message and GroupBy attributes,
+// and, at the end of each window, one summary record per group is emitted
+// instead of the raw records:
+//
+//	level=INFO msg="cache hit" cache=users aggregate.count=1204 aggregate.window=1m0s aggregate.sum=... aggregate.min=... aggregate.max=... aggregate.p50=...
+//
+// Summaries carry the highest level of the group, the GroupBy attributes and
+// the summary group. They are emitted to the handler wrapped by the middleware,
+// without the attributes added with WithAttrs, since a group may contain records
+// of several loggers. Pending summaries are emitted by Flush and Close (see Shutdown).
+// The window attribute is the time elapsed since the window opened, which is
+// shorter than Window when summaries are emitted early by Flush or Close.
+//
+// Example usage:
+//
+//	logger := slog.New(
+//	    slogmulti.
+//	        Pipe(slogmulti.Aggregate(slogmulti.AggregateOption{
+//	            Window:    time.Minute,
+//	            Predicate: slogmulti.MessageIs("cache hit"),
+//	            GroupBy:   []string{"cache"},
+//	            Value:     "latency",
+//	        })).
+//	        Handler(sink),
+//	)
+//
+// Args:
+//
+//	opts: The aggregation configuration
+//
+// Returns:
+//
+//	A middleware aggregating records
+func Aggregate(opts AggregateOption) Middleware {
+	if opts.Window <= 0 {
+		opts.Window = time.Minute
+	}
+	if opts.Percentiles == nil {
+		opts.Percentiles = []float64{0.5, 0.9, 0.99}
+	}
+	for _, p := range opts.Percentiles {
+		if p < 0 || p > 1 {
+			panic("slog-multi: percentiles must be between 0 and 1")
+		}
+	}
+	if opts.MaxSamples <= 0 {
+		opts.MaxSamples = 1000
+	}
+	if opts.SummaryKey == "" {
+		opts.SummaryKey = "aggregate"
+	}
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+
+	return func(next slog.Handler) slog.Handler {
+		if next == nil {
+			panic("slog-multi: next is required")
+		}
+
+		return &AggregateHandler{
+			next: next,
+			state: &aggregateState{
+				opts:   opts,
+				next:   next,
+				groups: map[string]*aggregateGroup{},
+			},
+			groups: []string{},
+			attrs:  []slog.Attr{},
+		}
+	}
+}
+
+// Ensure AggregateHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*AggregateHandler)(nil)
+
+// AggregateHandler is the handler created by the Aggregate middleware.
+type AggregateHandler struct {
+	next  slog.Handler
+	state *aggregateState
+
+	// groups and attrs are tracked to look up GroupBy and Value attributes
+	groups []string
+	attrs  []slog.Attr
+}
+
+// aggregateState is shared by an AggregateHandler and the handlers derived with WithAttrs and WithGroup.
+type aggregateState struct {
+	opts AggregateOption
+	// next is the handler wrapped by the middleware, receiving summaries
+	next slog.Handler
+
+	mu     sync.Mutex
+	groups map[string]*aggregateGroup
+	order  []string
+	// windowStart and windowEnd are zero when no window is open
+	windowStart time.Time
+	windowEnd   time.Time
+	stopTimer   func() bool
+}
+
+type aggregateGroup struct {
+	message string
+	level   slog.Level
+	groupBy []slog.Attr
+	count   int64
+
+	// values is the number of values, summarized by sum, min, max and samples
+	values  int64
+	sum     float64
+	min     float64
+	max     float64
+	samples []float64
+	// duration is true when the first value is a duration
+	duration bool
+}
+
+// addValue summarizes a value, keeping at most maxSamples uniformly sampled values.
+func (g *aggregateGroup) addValue(value float64, duration bool, maxSamples int) {
+	g.values++
+	g.sum += value
+	if g.values == 1 {
+		g.duration = duration
+	}
+	if g.values == 1 || value < g.min {
+		g.min = value
+	}
+	if g.values == 1 || value > g.max {
+		g.max = value
+	}
+
+	if len(g.samples) < maxSamples {
+		g.samples = append(g.samples, value)
+	} else if i := rand.Int64N(g.values); i < int64(maxSamples) {
+		g.samples[i] = value
+	}
+}
+
+// Enabled checks if the next handler is enabled for the given log level.
+// This method implements the slog.Handler interface requirement.
+func (h *AggregateHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle aggregates the record, or forwards it to the next handler when it
+// does not match the predicate.
+// This method implements the slog.Handler interface requirement.
+func (h *AggregateHandler) Handle(ctx context.Context, r slog.Record) error {
+	opts := h.state.opts
+	attrs := mergeRecordAttrs(h.attrs, h.groups, &r)
+
+	// like routes, the predicate sees the attributes added with WithAttrs
+	if opts.Predicate != nil && !opts.Predicate(ctx, recordWithAttrs(r, attrs)) {
+		return h.next.Handle(ctx, r)
+	}
+
+	groupBy := []slog.Attr{}
+	var key strings.Builder
+	key.WriteString(r.Message)
+	for _, path := range opts.GroupBy {
+		key.WriteByte(0)
+		keys := slogattr.SplitPath(path)
+		if attr, ok := slogattr.FindByPath(attrs, keys); ok {
+			value := attr.Value.Resolve()
+			// the attribute keeps its location in the summary
+			groupBy = slogcommon.AppendAttrsToGroup(keys[:len(keys)-1], groupBy, slog.Attr{Key: keys[len(keys)-1], Value: value})
+			// the kind avoids collisions between values rendered alike (eg: 1 and "1")
+			key.WriteByte(1)
+			key.WriteByte(byte(value.Kind()))
+			key.WriteString(value.String())
+		}
+	}
+
+	var value float64
+	var hasValue, duration bool
+	if opts.Value != "" {
+		if attr, ok := slogattr.FindByPath(attrs, slogattr.SplitPath(opts.Value)); ok {
+			value, duration, hasValue = numericValue(attr.Value.Resolve())
+		}
+	}
+
+	return h.state.add(ctx, key.String(), r, groupBy, value, hasValue, duration)
+}
+
+// WithAttrs creates a new AggregateHandler sharing the same aggregation, with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *AggregateHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AggregateHandler{
+		next:   h.next.WithAttrs(attrs),
+		state:  h.state,
+		groups: h.groups,
+		attrs:  slogcommon.AppendAttrsToGroup(h.groups, h.attrs, attrs...),
+	}
+}
+
+// WithGroup creates a new AggregateHandler sharing the same aggregation, with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *AggregateHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, 0, len(h.groups)+1)
+	groups = append(groups, h.groups...)
+	groups = append(groups, name)
+
+	return &AggregateHandler{
+		next:   h.next.WithGroup(name),
+		state:  h.state,
+		groups: groups,
+		attrs:  h.attrs,
+	}
+}
+
+// Flush emits the pending summaries, then flushes the next handler.
+// This method implements the Flusher interface.
+func (h *AggregateHandler) Flush(ctx context.Context) error {
+	return errors.Join(h.state.emit(ctx), flushHandler(ctx, h.next))
+}
+
+// Close emits the pending summaries, then closes the next handler.
+// This method implements the Closer interface.
+func (h *AggregateHandler) Close(ctx context.Context) error {
+	return errors.Join(h.state.emit(ctx), closeHandler(ctx, h.next))
+}
+
+// Inspect describes the Aggregate middleware and the next handler.
+// This method implements the Inspector interface.
+func (h *AggregateHandler) Inspect() HandlerNode {
+	node := inspectNode("Aggregate", h.groups, h.attrs, h.next)
+	node.Name = h.state.opts.Window.String()
+	return node
+}
+
+func (s *aggregateState) add(ctx context.Context, key string, r slog.Record, groupBy []slog.Attr, value float64, hasValue bool, duration bool) error {
+	now := s.opts.Clock.Now()
+
+	// the window timer may be late: close the previous window first
+	var err error
+	s.mu.Lock()
+	expired := !s.windowEnd.IsZero() && !now.Before(s.windowEnd)
+	s.mu.Unlock()
+	if expired {
+		err = s.emit(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.windowEnd.IsZero() {
+		s.windowStart = now
+		s.windowEnd = now.Add(s.opts.Window)
+		s.stopTimer = s.opts.Clock.AfterFunc(s.opts.Window, func() {
+			_ = s.emit(context.Background())
+		})
+	}
+
+	group, ok := s.groups[key]
+	if !ok {
+		group = &aggregateGroup{
+			message: r.Message,
+			level:   r.Level,
+			groupBy: groupBy,
+		}
+		s.groups[key] = group
+		s.order = append(s.order, key)
+	}
+
+	group.count++
+	if r.Level > group.level {
+		group.level = r.Level
+	}
+	if hasValue {
+		group.addValue(value, duration, s.opts.MaxSamples)
+	}
+
+	return err
+}
+
+// emit sends one summary per group of the current window to the next handler.
+func (s *aggregateState) emit(ctx context.Context) error {
+	s.mu.Lock()
+	if s.stopTimer != nil {
+		s.stopTimer()
+		s.stopTimer = nil
+	}
+	groups, order, start := s.groups, s.order, s.windowStart
+	s.groups = map[string]*aggregateGroup{}
+	s.order = nil
+	s.windowStart = time.Time{}
+	s.windowEnd = time.Time{}
+	s.mu.Unlock()
+
+	now := s.opts.Clock.Now()
+	// the window may be closed early (Flush, Close) or late (timer delay)
+	elapsed := now.Sub(start)
+
+	var errs []error
+	for _, key := range order {
+		group := groups[key]
+		record := slog.NewRecord(now, group.level, group.message, 0)
+		record.AddAttrs(group.groupBy...)
+		record.AddAttrs(slog.Attr{Key: s.opts.SummaryKey, Value: slog.GroupValue(s.summary(group, elapsed)...)})
+
+		if !s.next.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := try(func() error { return s.next.Handle(ctx, record) }); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *aggregateState) summary(group *aggregateGroup, window time.Duration) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.Int64("count", group.count),
+		slog.Duration("window", window),
+	}
+	if group.values == 0 {
+		return attrs
+	}
+
+	samples := group.samples
+	sort.Float64s(samples)
+
+	number := func(key string, v float64) slog.Attr {
+		if group.duration {
+			return slog.Duration(key, time.Duration(v))
+		}
+		return slog.Float64(key, v)
+	}
+
+	attrs = append(
+		attrs,
+		number("sum", group.sum),
+		number("min", group.min),
+		number("max", group.max),
+	)
+	for _, p := range s.opts.Percentiles {
+		attrs = append(attrs, number(percentileKey(p), percentile(samples, p)))
+	}
+
+	return attrs
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// percentileKey formats 0.5 as "p50" and 0.999 as "p99.9".
+func percentileKey(p float64) string {
+	return "p" + strconv.FormatFloat(p*100, 'f', -1, 64)
+}
+
+// numericValue converts a numeric value to float64.
+func numericValue(value slog.Value) (v float64, duration bool, ok bool) {
+	switch value.Kind() {
+	case slog.KindInt64:
+		return float64(value.Int64()), false, true
+	case slog.KindUint64:
+		return float64(value.Uint64()), false, true
+	case slog.KindFloat64:
+		return value.Float64(), false, true
+	case slog.KindDuration:
+		return float64(value.Duration()), true, true
+	}
+
+	return 0, false, false
+}
@@ -0,0 +1,329 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/samber/slog-multi/slogmultitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAggregateLogger(opts AggregateOption) (*slog.Logger, *slogmultitest.RecordingHandler, *slogmultitest.FakeClock) {
+	clock := slogmultitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	opts.Clock = clock
+
+	recorder := slogmultitest.NewRecordingHandler(nil)
+	logger := slog.New(Pipe(Aggregate(opts)).Handler(recorder))
+
+	return logger, recorder, clock
+}
+
+func TestAggregate_SummaryPerGroup(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, recorder, clock := newAggregateLogger(AggregateOption{
+		Window:      time.Minute,
+		Predicate:   MessageIs("cache hit"),
+		GroupBy:     []string{"cache"},
+		Value:       "latency",
+		Percentiles: []float64{0.5, 0.99},
+	})
+
+	for i := 1; i <= 10; i++ {
+		logger.Info("cache hit", "cache", "users", "latency", time.Duration(i)*time.Millisecond)
+	}
+	logger.Warn("cache hit", "cache", "sessions", "latency", 5*time.Millisecond)
+	logger.Info("request", "status", 200)
+
+	// raw records are not forwarded, other records are
+	is.Equal([]string{"request"}, recorder.Messages())
+
+	clock.Advance(59 * time.Second)
+	is.Equal(1, recorder.Len())
+
+	clock.Advance(time.Second)
+	is.Equal(3, recorder.Len())
+
+	records := recorder.Records()
+	users, sessions := records[1], records[2]
+
+	is.Equal("cache hit", users.Message)
+	is.Equal(slog.LevelInfo, users.Level)
+	is.Equal(clock.Now(), users.Time)
+	slogmultitest.AssertAttr(t, users, "cache", "users")
+	slogmultitest.AssertAttr(t, users, "aggregate.count", int64(10))
+	slogmultitest.AssertAttr(t, users, "aggregate.window", time.Minute)
+	slogmultitest.AssertAttr(t, users, "aggregate.sum", 55*time.Millisecond)
+	slogmultitest.AssertAttr(t, users, "aggregate.min", time.Millisecond)
+	slogmultitest.AssertAttr(t, users, "aggregate.max", 10*time.Millisecond)
+	slogmultitest.AssertAttr(t, users, "aggregate.p50", 5*time.Millisecond)
+	slogmultitest.AssertAttr(t, users, "aggregate.p99", 10*time.Millisecond)
+
+	is.Equal(slog.LevelWarn, sessions.Level)
+	slogmultitest.AssertAttr(t, sessions, "cache", "sessions")
+	slogmultitest.AssertAttr(t, sessions, "aggregate.count", int64(1))
+
+	// the next window starts empty
+	clock.Advance(time.Hour)
+	is.Equal(3, recorder.Len())
+	is.Equal(0, clock.Timers())
+}
+
+func TestAggregate_NumericValues(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, recorder, clock := newAggregateLogger(AggregateOption{
+		Window: time.Second,
+		Value:  "payload.size",
+	})
+
+	logger.Info("upload", slog.Group("payload", slog.Int("size", 300)))
+	logger.Info("upload", slog.Group("payload", slog.Float64("size", 100.5)))
+	logger.Info("upload", slog.Group("payload", slog.Uint64("size", 200)))
+	logger.Info("upload", slog.Group("payload", slog.String("size", "n/a")))
+	logger.Info("download")
+
+	clock.Advance(time.Second)
+	is.Equal([]string{"upload", "download"}, recorder.Messages())
+
+	upload, _ := recorder.FindByMessage("upload")
+	slogmultitest.AssertAttr(t, upload, "aggregate.count", int64(4))
+	slogmultitest.AssertAttr(t, upload, "aggregate.sum", 600.5)
+	slogmultitest.AssertAttr(t, upload, "aggregate.min", 100.5)
+	slogmultitest.AssertAttr(t, upload, "aggregate.max", 300.0)
+	slogmultitest.AssertAttr(t, upload, "aggregate.p50", 200.0)
+	slogmultitest.AssertAttr(t, upload, "aggregate.p90", 300.0)
+
+	download, _ := recorder.FindByMessage("download")
+	slogmultitest.AssertAttr(t, download, "aggregate.count", int64(1))
+	slogmultitest.AssertNoAttr(t, download, "aggregate.sum")
+}
+
+func TestAggregate_GroupByKinds(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, recorder, clock := newAggregateLogger(AggregateOption{
+		Window:  time.Second,
+		GroupBy: []string{"id"},
+	})
+
+	// values rendered alike are not merged
+	logger.Info("request", "id", 1)
+	logger.Info("request", "id", "1")
+	logger.Info("request", "id", 1)
+
+	clock.Advance(time.Second)
+	is.Equal(2, recorder.Len())
+
+	records := recorder.Records()
+	slogmultitest.AssertAttr(t, records[0], "id", int64(1))
+	slogmultitest.AssertAttr(t, records[0], "aggregate.count", int64(2))
+	slogmultitest.AssertAttr(t, records[1], "id", "1")
+	slogmultitest.AssertAttr(t, records[1], "aggregate.count", int64(1))
+}
+
+func TestAggregate_MixedValueKinds(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, recorder, clock := newAggregateLogger(AggregateOption{
+		Window:      time.Second,
+		Value:       "latency",
+		Percentiles: []float64{1},
+	})
+
+	// the first value decides how the summary is rendered
+	logger.Info("request", "latency", 2*time.Millisecond)
+	logger.Info("request", "latency", 1000)
+
+	logger.Info("upload", "latency", 1000)
+	logger.Info("upload", "latency", 2*time.Millisecond)
+
+	clock.Advance(time.Second)
+	is.Equal(2, recorder.Len())
+
+	request, _ := recorder.FindByMessage("request")
+	slogmultitest.AssertAttr(t, request, "aggregate.min", time.Microsecond)
+	slogmultitest.AssertAttr(t, request, "aggregate.max", 2*time.Millisecond)
+
+	upload, _ := recorder.FindByMessage("upload")
+	slogmultitest.AssertAttr(t, upload, "aggregate.min", 1000.0)
+	slogmultitest.AssertAttr(t, upload, "aggregate.max", float64(2*time.Millisecond))
+}
+
+func TestAggregate_MaxSamples(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, recorder, clock := newAggregateLogger(AggregateOption{
+		Window:      time.Second,
+		Value:       "latency",
+		Percentiles: []float64{0, 0.5, 1},
+		MaxSamples:  10,
+	})
+	state := logger.Handler().(*AggregateHandler).state
+
+	for i := 1; i <= 1000; i++ {
+		logger.Info("request", "latency", i)
+	}
+
+	state.mu.Lock()
+	is.Len(state.groups["request"].samples, 10)
+	state.mu.Unlock()
+
+	clock.Advance(time.Second)
+	is.Equal(1, recorder.Len())
+
+	// count, sum, min and max are exact, percentiles are estimated from the samples
+	request := recorder.Records()[0]
+	slogmultitest.AssertAttr(t, request, "aggregate.count", int64(1000))
+	slogmultitest.AssertAttr(t, request, "aggregate.sum", 500500.0)
+	slogmultitest.AssertAttr(t, request, "aggregate.min", 1.0)
+	slogmultitest.AssertAttr(t, request, "aggregate.max", 1000.0)
+
+	p0, ok := request.AttrAt("aggregate.p0")
+	is.True(ok)
+	p50, ok := request.AttrAt("aggregate.p50")
+	is.True(ok)
+	p100, ok := request.AttrAt("aggregate.p100")
+	is.True(ok)
+	is.LessOrEqual(1.0, p0.Float64())
+	is.LessOrEqual(p0.Float64(), p50.Float64())
+	is.LessOrEqual(p50.Float64(), p100.Float64())
+	is.LessOrEqual(p100.Float64(), 1000.0)
+}
+
+func TestAggregate_WithAttrsAndGroups(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, recorder, clock := newAggregateLogger(AggregateOption{
+		Window:    time.Minute,
+		Predicate: AttrValueIs("tenant", "acme"),
+		GroupBy:   []string{"tenant", "http.route"},
+	})
+
+	tenant := logger.With("tenant", "acme")
+	tenant.WithGroup("http").Info("request", "route", "/users", "request_id", 1)
+	tenant.WithGroup("http").Info("request", "route", "/users", "request_id", 2)
+	tenant.WithGroup("http").Info("request", "route", "/orders", "request_id", 3)
+	logger.With("tenant", "other").Info("request")
+
+	// unmatched records keep the attributes of their logger
+	is.Equal(1, recorder.Len())
+	other, _ := recorder.Last()
+	slogmultitest.AssertAttr(t, other, "tenant", "other")
+
+	clock.Advance(time.Minute)
+	is.Equal(3, recorder.Len())
+
+	records := recorder.Records()
+	slogmultitest.AssertAttr(t, records[1], "tenant", "acme")
+	slogmultitest.AssertAttr(t, records[1], "http.route", "/users")
+	slogmultitest.AssertAttr(t, records[1], "aggregate.count", int64(2))
+	slogmultitest.AssertNoAttr(t, records[1], "http.request_id")
+	slogmultitest.AssertAttr(t, records[2], "http.route", "/orders")
+	slogmultitest.AssertAttr(t, records[2], "aggregate.count", int64(1))
+}
+
+func TestAggregate_FlushAndClose(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	clock := slogmultitest.NewFakeClock(time.Now())
+	recorder := slogmultitest.NewRecordingHandler(nil)
+	sink := &lifecycleHandler{}
+	handler := Pipe(Aggregate(AggregateOption{Window: time.Hour, SummaryKey: "stats", Clock: clock})).Handler(Fanout(recorder, sink))
+	logger := slog.New(handler)
+
+	logger.Info("tick")
+	clock.Advance(10 * time.Minute)
+	logger.With("k", "v").Info("tick")
+
+	is.NoError(Flush(context.Background(), handler))
+	is.Equal(int64(1), sink.flushCount.Load())
+	is.Equal(0, clock.Timers())
+
+	is.Equal(1, recorder.Len())
+	record, _ := recorder.Last()
+	slogmultitest.AssertAttr(t, record, "stats.count", int64(2))
+	slogmultitest.AssertNoAttr(t, record, "k")
+	// the window is closed early
+	slogmultitest.AssertAttr(t, record, "stats.window", 10*time.Minute)
+
+	logger.Info("tock")
+	clock.Advance(time.Minute)
+	is.NoError(Shutdown(context.Background(), handler))
+	is.Equal(int64(1), sink.closeCount.Load())
+	is.Equal([]string{"tick", "tock"}, recorder.Messages())
+	record, _ = recorder.Last()
+	slogmultitest.AssertAttr(t, record, "stats.window", time.Minute)
+
+	// nothing is pending anymore
+	is.NoError(Flush(context.Background(), handler))
+	is.Equal(2, recorder.Len())
+}
+
+func TestAggregate_LateTimer(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	recorder := slogmultitest.NewRecordingHandler(nil)
+	clock := &lateClock{FakeClock: slogmultitest.NewFakeClock(time.Now())}
+	logger := slog.New(Pipe(Aggregate(AggregateOption{Window: time.Minute, Clock: clock})).Handler(recorder))
+
+	logger.Info("tick")
+	clock.Advance(2 * time.Minute)
+	is.Equal(0, recorder.Len())
+
+	// the expired window is emitted before a new one starts
+	logger.Info("tick")
+	is.Equal(1, recorder.Len())
+	record, _ := recorder.Last()
+	slogmultitest.AssertAttr(t, record, "aggregate.count", int64(1))
+}
+
+// lateClock never fires its timers.
+type lateClock struct {
+	*slogmultitest.FakeClock
+}
+
+func (c *lateClock) AfterFunc(time.Duration, func()) func() bool {
+	return func() bool { return true }
+}
+
+func TestAggregate_Errors(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.PanicsWithValue("slog-multi: percentiles must be between 0 and 1", func() {
+		Aggregate(AggregateOption{Percentiles: []float64{99}})
+	})
+	is.PanicsWithValue("slog-multi: next is required", func() {
+		Aggregate(AggregateOption{})(nil)
+	})
+
+	clock := slogmultitest.NewFakeClock(time.Now())
+	failing := slogmultitest.NewFailingHandler(errors.New("boom"), slogmultitest.Always(), nil)
+	handler := Aggregate(AggregateOption{Clock: clock})(failing)
+	is.NoError(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "tick", 0)))
+	is.EqualError(Flush(context.Background(), handler), "boom")
+}
+
+func TestAggregate_Inspect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	handler := Aggregate(AggregateOption{Window: 10 * time.Second})(&noopHandler{})
+	node := Inspect(handler.WithAttrs([]slog.Attr{slog.String("env", "prod")}))
+
+	is.Equal("Aggregate", node.Type)
+	is.Equal("10s", node.Name)
+	is.Len(node.Children, 1)
+}
@@ -0,0 +1,169 @@
+package slogmulti
+
+import (
+	"log/slog"
+
+	slogcommon "github.com/samber/slog-common"
+)
+
+// updateAttrsByPath calls fn for every attribute located at the given path and
+// returns a new attribute list. When fn returns false, the attribute is removed.
+// The input slice is never mutated, since it might be shared between records.
+func updateAttrsByPath(attrs []slog.Attr, path []string, fn func(slog.Attr) (slog.Attr, bool)) []slog.Attr {
+	if len(path) == 0 {
+		return attrs
+	}
+
+	output := make([]slog.Attr, 0, len(attrs))
+
+	for _, attr := range attrs {
+		if attr.Key != path[0] {
+			output = append(output, attr)
+			continue
+		}
+
+		if len(path) == 1 {
+			if attr, keep := fn(attr); keep {
+				output = append(output, attr)
+			}
+			continue
+		}
+
+		value := attr.Value.Resolve()
+		if value.Kind() != slog.KindGroup {
+			output = append(output, attr)
+			continue
+		}
+
+		children := updateAttrsByPath(value.Group(), path[1:], fn)
+		if len(children) > 0 {
+			output = append(output, slog.Attr{Key: attr.Key, Value: slog.GroupValue(children...)})
+		}
+	}
+
+	return output
+}
+
+// removeAttrsByPath removes every attribute located at the given path.
+// It returns the new attribute list and the removed attributes.
+func removeAttrsByPath(attrs []slog.Attr, path []string) ([]slog.Attr, []slog.Attr) {
+	removed := []slog.Attr{}
+	output := updateAttrsByPath(attrs, path, func(attr slog.Attr) (slog.Attr, bool) {
+		removed = append(removed, attr)
+		return attr, false
+	})
+	return output, removed
+}
+
+// setAttrByPath adds an attribute under the given group path, creating missing
+// groups on the way. An attribute with the same key in the target group is replaced.
+// Existing values are never dropped: when a non-group attribute holds the key of
+// a group of the path, attrs is returned unchanged with false.
+func setAttrByPath(attrs []slog.Attr, groups []string, attr slog.Attr) ([]slog.Attr, bool) {
+	if len(groups) == 0 {
+		output := make([]slog.Attr, 0, len(attrs)+1)
+		for _, a := range attrs {
+			if a.Key != attr.Key {
+				output = append(output, a)
+			}
+		}
+		return append(output, attr), true
+	}
+
+	// nest into the last group having the same key
+	blocked := false
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key != groups[0] {
+			continue
+		}
+
+		value := attrs[i].Value.Resolve()
+		if value.Kind() != slog.KindGroup {
+			blocked = true
+			continue
+		}
+
+		children, ok := setAttrByPath(value.Group(), groups[1:], attr)
+		if !ok {
+			return attrs, false
+		}
+
+		output := make([]slog.Attr, len(attrs))
+		copy(output, attrs)
+		output[i] = slog.Attr{
+			Key:   groups[0],
+			Value: slog.GroupValue(children...),
+		}
+		return output, true
+	}
+
+	if blocked {
+		return attrs, false
+	}
+
+	children, _ := setAttrByPath(nil, groups[1:], attr)
+
+	output := make([]slog.Attr, 0, len(attrs)+1)
+	output = append(output, attrs...)
+	return append(output, slog.Attr{
+		Key:   groups[0],
+		Value: slog.GroupValue(children...),
+	}), true
+}
+
+// appendGroupedAttrs appends attrs under the given groups to the attributes
+// accumulated by an inline handler. Attributes added under the same groups
+// share the last group attribute having the same key. Unlike
+// slogcommon.AppendAttrsToGroup, no attribute is de-duplicated, so that
+// attributes added before any group are kept as a flat list.
+// The input slice is never mutated.
+func appendGroupedAttrs(actual []slog.Attr, groups []string, attrs []slog.Attr) []slog.Attr {
+	if len(groups) == 0 {
+		output := make([]slog.Attr, 0, len(actual)+len(attrs))
+		output = append(output, actual...)
+		return append(output, attrs...)
+	}
+
+	for i := len(actual) - 1; i >= 0; i-- {
+		if actual[i].Key == groups[0] && actual[i].Value.Kind() == slog.KindGroup {
+			output := make([]slog.Attr, len(actual))
+			copy(output, actual)
+			output[i] = slog.Attr{
+				Key:   groups[0],
+				Value: slog.GroupValue(appendGroupedAttrs(actual[i].Value.Group(), groups[1:], attrs)...),
+			}
+			return output
+		}
+	}
+
+	output := make([]slog.Attr, 0, len(actual)+1)
+	output = append(output, actual...)
+	return append(output, slog.Attr{
+		Key:   groups[0],
+		Value: slog.GroupValue(appendGroupedAttrs(nil, groups[1:], attrs)...),
+	})
+}
+
+// mergeRecordAttrs nests the record attributes under the current groups and merges
+// them into the attributes accumulated with WithAttrs. Unlike
+// slogcommon.AppendRecordAttrsToAttrs, a single group is created for all record attributes.
+func mergeRecordAttrs(attrs []slog.Attr, groups []string, r *slog.Record) []slog.Attr {
+	if r.NumAttrs() == 0 {
+		return attrs
+	}
+
+	recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		recordAttrs = append(recordAttrs, attr)
+		return true
+	})
+
+	return slogcommon.AppendAttrsToGroup(groups, attrs, recordAttrs...)
+}
+
+// recordWithAttrs creates a copy of the record metadata with a new list of attributes.
+func recordWithAttrs(r slog.Record, attrs []slog.Attr) slog.Record {
+	record := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	record.AddAttrs(attrs...)
+	return record
+}
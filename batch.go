@@ -0,0 +1,353 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	slogcommon "github.com/samber/slog-common"
+	"github.com/samber/slog-multi/internal/slogattr"
+)
+
+// ErrBatchClosed is returned when handling a record after the batch handler has been closed.
+var ErrBatchClosed = errors.New("slog-multi: batch handler is closed")
+
+// Entry is a record collected by a batch handler. Its attributes are resolved:
+// attributes added with WithAttrs and record attributes are nested under their
+// groups, and slog.LogValuer values are resolved.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	PC      uintptr
+	Attrs   []slog.Attr
+}
+
+// Map converts the attributes of the entry to nested maps: groups are map[string]any.
+func (e Entry) Map() map[string]any {
+	return slogcommon.AttrsToMap(e.Attrs...)
+}
+
+// BatchOption configures a batch handler.
+type BatchOption struct {
+	// Level is the minimum level of collected records. Default: slog.LevelInfo.
+	Level slog.Leveler
+
+	// MaxSize is the number of entries triggering a flush. Default: 100.
+	MaxSize int
+	// MaxBytes is the approximate size of the entries (message, keys and
+	// values) triggering a flush. Default: no limit.
+	MaxBytes int
+	// Interval is the maximum time an entry waits before being flushed.
+	// Default: no time threshold, entries wait for MaxSize, MaxBytes or Flush.
+	Interval time.Duration
+	// Timeout bounds the flushes triggered by a threshold, retries included.
+	// These flushes do not inherit the cancellation of the record context, which
+	// belongs to the log call that filled the batch. Default: 10s.
+	Timeout time.Duration
+
+	// MaxRetries is the number of retries of a failed flush. Default: 0.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled on every retry. Default: 100ms.
+	RetryBackoff time.Duration
+	// ShouldRetry decides whether a flush error is retried. Default: every error is retried.
+	ShouldRetry func(err error) bool
+
+	// OnError is called with the entries dropped after a failed flush, including
+	// flushes triggered by Interval, whose error cannot be returned.
+	OnError func(ctx context.Context, entries []Entry, err error)
+}
+
+// Batch creates a handler collecting resolved records and flushing them to fn
+// when MaxSize entries, MaxBytes or the Interval threshold is reached. Failed
+// flushes are retried with an exponential backoff. Pending entries are flushed
+// by Flush and Close (see Shutdown).
+//
+// A batch filled by a record is flushed synchronously by the goroutine logging
+// this record, and the flush error is returned by Handle. The flush keeps the
+// values of the record context, but not its cancellation: the batch contains
+// entries of other log calls. Flushes are serialized, so that fn receives
+// batches in order.
+//
+// Example usage:
+//
+//	handler := slogmulti.Batch(
+//	    func(ctx context.Context, entries []slogmulti.Entry) error {
+//	        return client.BulkInsert(ctx, entries)
+//	    },
+//	    slogmulti.BatchOption{MaxSize: 500, Interval: 5 * time.Second, MaxRetries: 3},
+//	)
+//	defer slogmulti.Shutdown(context.Background(), handler)
+//
+// Args:
+//
+//	fn: Sends a batch of entries to the bulk sink
+//	opts: The thresholds and retry policy
+//
+// Returns:
+//
+//	A batch handler, implementing Flusher and Closer
+func Batch(fn func(ctx context.Context, entries []Entry) error, opts BatchOption) *BatchHandler {
+	if fn == nil {
+		panic("slog-multi: flush function is required")
+	}
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = 100
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 100 * time.Millisecond
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	return &BatchHandler{
+		batch: &batchState{
+			fn:   fn,
+			opts: opts,
+		},
+		groups: []string{},
+		attrs:  []slog.Attr{},
+	}
+}
+
+// batchState is shared by a batch handler and the handlers derived with WithAttrs and WithGroup.
+type batchState struct {
+	fn   func(ctx context.Context, entries []Entry) error
+	opts BatchOption
+
+	mu      sync.Mutex
+	entries []Entry
+	bytes   int
+	timer   *time.Timer
+	closed  bool
+
+	// flushMu serializes flushes, so that batches are sent in order
+	flushMu sync.Mutex
+}
+
+// Ensure BatchHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*BatchHandler)(nil)
+
+// BatchHandler collects records and flushes them in batches. See Batch.
+type BatchHandler struct {
+	batch  *batchState
+	groups []string
+	// attrs are resolved and nested under their groups
+	attrs []slog.Attr
+}
+
+// Enabled checks the minimum level of the batch handler.
+// This method implements the slog.Handler interface requirement.
+func (h *BatchHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.batch.opts.Level.Level()
+}
+
+// Handle adds the record to the pending batch, and flushes the batch when a
+// size threshold is reached.
+// This method implements the slog.Handler interface requirement.
+func (h *BatchHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := h.attrs
+	if r.NumAttrs() > 0 {
+		attrs = slogattr.Resolve(mergeRecordAttrs(h.attrs, h.groups, &r))
+	}
+
+	entry := Entry{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		PC:      r.PC,
+		Attrs:   attrs,
+	}
+
+	full, err := h.batch.add(entry)
+	if err != nil || !full {
+		return err
+	}
+
+	return h.batch.flushDetached(ctx)
+}
+
+// WithAttrs creates a new BatchHandler sharing the same batch, with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *BatchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs = slogattr.Resolve(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+
+	return &BatchHandler{
+		batch:  h.batch,
+		groups: h.groups,
+		attrs:  slogcommon.AppendAttrsToGroup(h.groups, h.attrs, attrs...),
+	}
+}
+
+// WithGroup creates a new BatchHandler sharing the same batch, with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *BatchHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, 0, len(h.groups)+1)
+	groups = append(groups, h.groups...)
+	groups = append(groups, name)
+
+	return &BatchHandler{
+		batch:  h.batch,
+		groups: groups,
+		attrs:  h.attrs,
+	}
+}
+
+// Flush sends the pending entries.
+// This method implements the Flusher interface.
+func (h *BatchHandler) Flush(ctx context.Context) error {
+	return h.batch.flush(ctx)
+}
+
+// Close sends the pending entries. Records handled afterward are rejected with ErrBatchClosed.
+// This method implements the Closer interface.
+func (h *BatchHandler) Close(ctx context.Context) error {
+	h.batch.mu.Lock()
+	h.batch.closed = true
+	h.batch.mu.Unlock()
+
+	return h.batch.flush(ctx)
+}
+
+// Pending returns the number of entries waiting for a flush.
+func (h *BatchHandler) Pending() int {
+	h.batch.mu.Lock()
+	defer h.batch.mu.Unlock()
+
+	return len(h.batch.entries)
+}
+
+// Inspect describes the batch handler and its accumulated groups and attributes.
+// This method implements the Inspector interface.
+func (h *BatchHandler) Inspect() HandlerNode {
+	return inspectNode("Batch", h.groups, h.attrs)
+}
+
+// add appends an entry to the pending batch and reports whether a size threshold is reached.
+func (b *batchState) add(entry Entry) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return false, ErrBatchClosed
+	}
+
+	// the first entry of a batch arms the time threshold
+	if len(b.entries) == 0 && b.opts.Interval > 0 {
+		b.timer = time.AfterFunc(b.opts.Interval, func() {
+			_ = b.flushDetached(context.Background())
+		})
+	}
+
+	b.entries = append(b.entries, entry)
+	if b.opts.MaxBytes > 0 {
+		b.bytes += entrySize(entry)
+	}
+
+	return len(b.entries) >= b.opts.MaxSize || (b.opts.MaxBytes > 0 && b.bytes >= b.opts.MaxBytes), nil
+}
+
+// take removes the pending entries.
+func (b *batchState) take() []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	entries := b.entries
+	b.entries = nil
+	b.bytes = 0
+	return entries
+}
+
+// flushDetached sends the pending entries, without the cancellation of ctx but
+// within the flush timeout.
+func (b *batchState) flushDetached(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), b.opts.Timeout)
+	defer cancel()
+
+	return b.flush(ctx)
+}
+
+// flush sends the pending entries, with retries.
+func (b *batchState) flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	entries := b.take()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := b.send(ctx, entries)
+	if err != nil && b.opts.OnError != nil {
+		b.opts.OnError(ctx, entries, err)
+	}
+
+	return err
+}
+
+func (b *batchState) send(ctx context.Context, entries []Entry) error {
+	backoff := b.opts.RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := try(func() error { return b.fn(ctx, entries) })
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= b.opts.MaxRetries || (b.opts.ShouldRetry != nil && !b.opts.ShouldRetry(err)) {
+			return fmt.Errorf("slog-multi: batch of %d entries dropped after %d attempt(s): %w", len(entries), attempt+1, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("slog-multi: batch of %d entries dropped after %d attempt(s): %w", len(entries), attempt+1, errors.Join(err, ctx.Err()))
+		}
+
+		backoff *= 2
+	}
+}
+
+// entrySize estimates the size of an entry, from its message, keys and values.
+func entrySize(entry Entry) int {
+	return len(entry.Message) + attrsSize(entry.Attrs)
+}
+
+func attrsSize(attrs []slog.Attr) int {
+	size := 0
+	for _, attr := range attrs {
+		size += len(attr.Key)
+		if attr.Value.Kind() == slog.KindGroup {
+			size += attrsSize(attr.Value.Group())
+			continue
+		}
+		if attr.Value.Kind() == slog.KindString {
+			size += len(attr.Value.String())
+		} else {
+			size += 8
+		}
+	}
+	return size
+}
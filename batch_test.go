@@ -0,0 +1,278 @@
+package slogmulti
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// batchCollector is an httptest server acting as a bulk collector.
+type batchCollector struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	batches  [][]map[string]any
+	failures atomic.Int64
+}
+
+func newBatchCollector(failures int64) *batchCollector {
+	c := &batchCollector{}
+	c.failures.Store(failures)
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		batch := []map[string]any{}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		c.mu.Lock()
+		c.batches = append(c.batches, batch)
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return c
+}
+
+func (c *batchCollector) Batches() [][]map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]map[string]any{}, c.batches...)
+}
+
+func (c *batchCollector) post(ctx context.Context, entries []Entry) error {
+	payload := make([]map[string]any, 0, len(entries))
+	for _, entry := range entries {
+		item := entry.Map()
+		item["msg"] = entry.Message
+		payload = append(payload, item)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.server.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	res, err := c.server.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("collector: %s", res.Status)
+	}
+	return nil
+}
+
+func TestBatchMaxSize(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	collector := newBatchCollector(0)
+	defer collector.server.Close()
+
+	handler := Batch(collector.post, BatchOption{MaxSize: 2})
+	logger := slog.New(handler).With("env", "prod").WithGroup("http")
+
+	logger.Info("a", "status", 200)
+	is.Empty(collector.Batches())
+	is.Equal(1, handler.Pending())
+
+	logger.Info("b", "status", 500)
+	logger.Info("c")
+	logger.Debug("below level")
+
+	is.Equal([][]map[string]any{
+		{
+			{"msg": "a", "env": "prod", "http": map[string]any{"status": float64(200)}},
+			{"msg": "b", "env": "prod", "http": map[string]any{"status": float64(500)}},
+		},
+	}, collector.Batches())
+
+	// final flush on Close
+	is.NoError(Shutdown(context.Background(), handler))
+	is.Len(collector.Batches(), 2)
+	is.Equal([]map[string]any{{"msg": "c", "env": "prod"}}, collector.Batches()[1])
+
+	is.ErrorIs(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "late", 0)), ErrBatchClosed)
+	is.NoError(handler.Close(context.Background()))
+}
+
+func TestBatchCancelledContext(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	collector := newBatchCollector(0)
+	defer collector.server.Close()
+
+	deadlines := make(chan bool, 1)
+	handler := Batch(func(ctx context.Context, entries []Entry) error {
+		_, ok := ctx.Deadline()
+		deadlines <- ok
+		return collector.post(ctx, entries)
+	}, BatchOption{MaxSize: 3, Timeout: time.Second})
+	logger := slog.New(handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the cancelled log call fills the batch: entries of other calls are still sent
+	logger.Info("a")
+	logger.Info("b")
+	is.NoError(handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "c", 0)))
+	is.True(<-deadlines)
+	is.Len(collector.Batches(), 1)
+	is.Len(collector.Batches()[0], 3)
+
+	// explicit flushes follow the caller context
+	logger.Info("d")
+	is.ErrorIs(handler.Flush(ctx), context.Canceled)
+	is.False(<-deadlines)
+}
+
+func TestBatchMaxBytes(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var mu sync.Mutex
+	sizes := []int{}
+	handler := Batch(func(ctx context.Context, entries []Entry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, len(entries))
+		return nil
+	}, BatchOption{MaxBytes: 20})
+
+	logger := slog.New(handler)
+	logger.Info("0123456789")
+	logger.Info("0123456789")
+	logger.Info("0123456789", "key", "0123456789")
+
+	mu.Lock()
+	is.Equal([]int{2, 1}, sizes)
+	mu.Unlock()
+}
+
+func TestBatchInterval(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	collector := newBatchCollector(0)
+	defer collector.server.Close()
+
+	handler := Batch(collector.post, BatchOption{Interval: 20 * time.Millisecond})
+	logger := slog.New(handler)
+	logger.Info("a")
+	logger.Info("b")
+
+	is.Eventually(func() bool { return len(collector.Batches()) == 1 }, time.Second, 5*time.Millisecond)
+	is.Len(collector.Batches()[0], 2)
+	is.Equal(0, handler.Pending())
+
+	is.NoError(handler.Close(context.Background()))
+}
+
+func TestBatchRetries(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	collector := newBatchCollector(2)
+	defer collector.server.Close()
+
+	handler := Batch(collector.post, BatchOption{MaxSize: 1, MaxRetries: 2, RetryBackoff: time.Millisecond})
+	is.NoError(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "retried", 0)))
+	is.Len(collector.Batches(), 1)
+
+	// retries exhausted
+	var dropped []Entry
+	collector.failures.Store(5)
+	handler = Batch(collector.post, BatchOption{
+		MaxSize:      1,
+		MaxRetries:   1,
+		RetryBackoff: time.Millisecond,
+		OnError: func(ctx context.Context, entries []Entry, err error) {
+			dropped = entries
+		},
+	})
+	err := handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "dropped", 0))
+	is.EqualError(err, "slog-multi: batch of 1 entries dropped after 2 attempt(s): collector: 503 Service Unavailable")
+	is.Len(dropped, 1)
+	is.Equal("dropped", dropped[0].Message)
+	is.EqualValues(3, collector.failures.Load())
+
+	// non-retryable errors
+	permanent := errors.New("permanent")
+	calls := 0
+	handler = Batch(func(ctx context.Context, entries []Entry) error {
+		calls++
+		return permanent
+	}, BatchOption{MaxSize: 1, MaxRetries: 3, ShouldRetry: func(err error) bool { return !errors.Is(err, permanent) }})
+	is.ErrorIs(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)), permanent)
+	is.Equal(1, calls)
+
+	// the flush timeout interrupts the backoff, not the cancellation of the record context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	handler = Batch(func(ctx context.Context, entries []Entry) error {
+		return permanent
+	}, BatchOption{MaxSize: 1, MaxRetries: 3, RetryBackoff: time.Hour, Timeout: 10 * time.Millisecond})
+	is.ErrorIs(handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)), context.DeadlineExceeded)
+}
+
+func TestBatchConcurrency(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var total atomic.Int64
+	handler := Batch(func(ctx context.Context, entries []Entry) error {
+		total.Add(int64(len(entries)))
+		return nil
+	}, BatchOption{MaxSize: 7, Interval: time.Millisecond})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			logger := slog.New(handler).With("goroutine", i)
+			for j := 0; j < 100; j++ {
+				logger.Info("hello")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	is.NoError(handler.Close(context.Background()))
+	is.EqualValues(800, total.Load())
+}
+
+func TestBatchInspect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	handler := Batch(func(ctx context.Context, entries []Entry) error { return nil }, BatchOption{}).
+		WithAttrs([]slog.Attr{slog.String("env", "prod")}).
+		WithGroup("g")
+	is.Equal("Batch groups=g attrs={env=prod}\n", Describe(handler))
+	is.Panics(func() { Batch(nil, BatchOption{}) })
+}
@@ -207,6 +207,40 @@ func BenchmarkRouterHandleParallel(b *testing.B) {
 	})
 }
 
+// BenchmarkRouterLargeTable compares the legacy evaluation, where every route
+// rebuilds the record, with the RouterHandler sharing a RecordView across routes.
+// Routes built with MatchAttr are indexed, so their dispatch does not depend on n.
+func BenchmarkRouterLargeTable(b *testing.B) {
+	for _, n := range []int{20, 100, 500} {
+		predicates := Router()
+		matchers := Router()
+		for i := 0; i < n; i++ {
+			tenant := fmt.Sprintf("tenant-%d", i)
+			predicates = predicates.Add(noopHandler{}, AttrValueIs("tenant", tenant))
+			matchers = matchers.AddMatchers(noopHandler{}, MatchAttr("tenant", tenant))
+		}
+
+		attrs := []slog.Attr{slog.String("env", "prod"), slog.String("tenant", fmt.Sprintf("tenant-%d", n-1))}
+		handlers := map[string]slog.Handler{
+			"legacy":     Fanout(predicates.handlers...).WithAttrs(attrs),
+			"predicates": predicates.Handler().WithAttrs(attrs),
+			"matchers":   matchers.Handler().WithAttrs(attrs),
+		}
+
+		for _, name := range []string{"legacy", "predicates", "matchers"} {
+			b.Run(fmt.Sprintf("routes=%d/%s", n, name), func(b *testing.B) {
+				b.ReportAllocs()
+				handler := handlers[name]
+				ctx := context.Background()
+				rec := benchRecord()
+				for i := 0; i < b.N; i++ {
+					_ = handler.Handle(ctx, rec)
+				}
+			})
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // FirstMatch benchmarks
 // ---------------------------------------------------------------------------
@@ -0,0 +1,177 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/samber/slog-multi/slogmultitest"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	forward := func(sink slog.Handler) func(ctx context.Context, groups []string, attrs []slog.Attr, record slog.Record) error {
+		return func(ctx context.Context, groups []string, attrs []slog.Attr, record slog.Record) error {
+			return sink.Handle(ctx, ResolveRecord(groups, attrs, record).Record())
+		}
+	}
+
+	cases := map[string]func(sink slog.Handler) slog.Handler{
+		"Fanout": func(sink slog.Handler) slog.Handler {
+			return Fanout(sink, &noopHandler{})
+		},
+		"Failover": func(sink slog.Handler) slog.Handler {
+			return Failover()(sink)
+		},
+		"Pool": func(sink slog.Handler) slog.Handler {
+			return Pool()(sink)
+		},
+		"FirstMatch": func(sink slog.Handler) slog.Handler {
+			return FirstMatch(&RoutableHandler{handler: sink})
+		},
+		// predicates and matchers see the attributes added with WithAttrs
+		"RoutableHandler": func(sink slog.Handler) slog.Handler {
+			return Router().AddRoute(Route{
+				Handler:    sink,
+				Predicates: []func(ctx context.Context, r slog.Record) bool{func(ctx context.Context, r slog.Record) bool { return true }},
+				Matchers:   []Matcher{MatcherFunc(func(ctx context.Context, view *RecordView) bool { return true })},
+			}).routes()[0]
+		},
+		"Router": func(sink slog.Handler) slog.Handler {
+			return Router().Add(sink, func(ctx context.Context, r slog.Record) bool { return true }).Handler()
+		},
+		"RouterFirstMatch": func(sink slog.Handler) slog.Handler {
+			return Router().AddMatchers(sink, MatcherFunc(func(ctx context.Context, view *RecordView) bool { return true })).FirstMatch().Handler()
+		},
+		"RouterDefault": func(sink slog.Handler) slog.Handler {
+			return Router().Add(&noopHandler{}, LevelIs(slog.Level(42))).Default(sink).Handler()
+		},
+		"RecoverHandlerError": func(sink slog.Handler) slog.Handler {
+			return RecoverHandlerError(func(context.Context, slog.Record, error) {})(sink)
+		},
+		"Pipe": func(sink slog.Handler) slog.Handler {
+			return Pipe(NewInlineMiddlewareWithOptions(), NewInlineMiddlewareWithOptions()).Handler(sink)
+		},
+		"InlineHandler": func(sink slog.Handler) slog.Handler {
+			return NewInlineHandler(
+				func(ctx context.Context, groups []string, attrs []slog.Attr, level slog.Level) bool { return true },
+				forward(sink),
+			)
+		},
+		"HandleInlineHandler": func(sink slog.Handler) slog.Handler {
+			return NewHandleInlineHandler(forward(sink))
+		},
+		"InlineHandlerWithOptionsResolved": func(sink slog.Handler) slog.Handler {
+			return NewInlineHandlerWithOptions(OnHandleResolved(func(ctx context.Context, record *ResolvedRecord) error {
+				return sink.Handle(ctx, record.Record())
+			}))
+		},
+		"InlineHandlerWithOptions": func(sink slog.Handler) slog.Handler {
+			return NewInlineHandlerWithOptions(OnHandle(func(ctx context.Context, record slog.Record, attrs []slog.Attr) error {
+				return sink.Handle(ctx, recordWithAttrs(record, attrs))
+			}))
+		},
+		"InlineMiddleware": func(sink slog.Handler) slog.Handler {
+			return NewInlineMiddleware(
+				func(ctx context.Context, level slog.Level, next func(context.Context, slog.Level) bool) bool {
+					return next(ctx, level)
+				},
+				func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+					return next(ctx, record)
+				},
+				func(attrs []slog.Attr, next func([]slog.Attr) slog.Handler) slog.Handler {
+					return next(attrs)
+				},
+				func(name string, next func(string) slog.Handler) slog.Handler {
+					return next(name)
+				},
+			)(sink)
+		},
+		"EnabledInlineMiddleware": func(sink slog.Handler) slog.Handler {
+			return NewEnabledInlineMiddleware(func(ctx context.Context, level slog.Level, next func(context.Context, slog.Level) bool) bool {
+				return next(ctx, level)
+			})(sink)
+		},
+		"HandleInlineMiddleware": func(sink slog.Handler) slog.Handler {
+			return NewHandleInlineMiddleware(func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+				return next(ctx, record)
+			})(sink)
+		},
+		"WithAttrsInlineMiddleware": func(sink slog.Handler) slog.Handler {
+			return NewWithAttrsInlineMiddleware(func(attrs []slog.Attr, next func([]slog.Attr) slog.Handler) slog.Handler {
+				return next(attrs)
+			})(sink)
+		},
+		"WithGroupInlineMiddleware": func(sink slog.Handler) slog.Handler {
+			return NewWithGroupInlineMiddleware(func(name string, next func(string) slog.Handler) slog.Handler {
+				return next(name)
+			})(sink)
+		},
+		"InlineMiddlewareWithOptions": func(sink slog.Handler) slog.Handler {
+			return NewInlineMiddlewareWithOptions()(sink)
+		},
+		"Transform": func(sink slog.Handler) slog.Handler {
+			return Transform(RenameAttr("unknown", "other"))(sink)
+		},
+		"ContextAttrs": func(sink slog.Handler) slog.Handler {
+			return ContextAttrs("")(sink)
+		},
+		"TraceAttrs": func(sink slog.Handler) slog.Handler {
+			return TraceAttrs()(sink)
+		},
+		"ErrorFormatter": func(sink slog.Handler) slog.Handler {
+			return ErrorFormatter(ErrorFormatterOption{})(sink)
+		},
+		"Enrich": func(sink slog.Handler) slog.Handler {
+			return Enrich(EnrichOption{DisableProcessAttrs: true, DisableBuildAttrs: true})(sink)
+		},
+		"LevelGate": func(sink slog.Handler) slog.Handler {
+			registry := NewLevelRegistry()
+			registry.Register("sink", slog.LevelDebug)
+			return LevelGate(registry, "sink")(sink)
+		},
+		"When": func(sink slog.Handler) slog.Handler {
+			return When(LevelIs(slog.LevelInfo), NewInlineMiddlewareWithOptions())(sink)
+		},
+		"Unless": func(sink slog.Handler) slog.Handler {
+			return Unless(LevelIs(slog.LevelInfo), NewInlineMiddlewareWithOptions())(sink)
+		},
+		"Tee": func(sink slog.Handler) slog.Handler {
+			return Tee(Branch("noop", &noopHandler{}, NewInlineMiddlewareWithOptions()))(sink)
+		},
+		"Branches": func(sink slog.Handler) slog.Handler {
+			return Branches(Branch("sink", sink, NewInlineMiddlewareWithOptions()))
+		},
+		// records which are not aggregated go straight to the sink
+		"Aggregate": func(sink slog.Handler) slog.Handler {
+			return Aggregate(AggregateOption{Predicate: MessageIs("cache hit")})(sink)
+		},
+		"Metrics": func(sink slog.Handler) slog.Handler {
+			return Metrics(NewMetricsRegistry(MetricsOption{Attrs: []string{"a"}}), "sink")(sink)
+		},
+		"Batch": func(sink slog.Handler) slog.Handler {
+			return Batch(func(ctx context.Context, entries []Entry) error {
+				for _, entry := range entries {
+					record := slog.NewRecord(entry.Time, entry.Level, entry.Message, entry.PC)
+					record.AddAttrs(entry.Attrs...)
+					if err := sink.Handle(ctx, record); err != nil {
+						return err
+					}
+				}
+				return nil
+			}, BatchOption{MaxSize: 1})
+		},
+		"LevelOverride": func(sink slog.Handler) slog.Handler {
+			return LevelOverride(nil, "sink")(sink)
+		},
+	}
+
+	for name, wrap := range cases {
+		wrap := wrap
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			slogmultitest.RunConformance(t, wrap)
+		})
+	}
+}
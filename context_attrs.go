@@ -0,0 +1,304 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextAttrsKey struct{}
+
+// WithContextAttrs returns a copy of ctx carrying the given attributes.
+// Attributes already stored in ctx are kept, and the new ones are appended.
+//
+// Example usage:
+//
+//	ctx = slogmulti.WithContextAttrs(ctx,
+//	    slog.String("request_id", requestID),
+//	    slog.String("user_id", userID),
+//	)
+//	logger.InfoContext(ctx, "user logged in")
+func WithContextAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+
+	previous := ContextAttrsFromContext(ctx)
+	merged := make([]slog.Attr, 0, len(previous)+len(attrs))
+	merged = append(merged, previous...)
+	merged = append(merged, attrs...)
+
+	return context.WithValue(ctx, contextAttrsKey{}, merged)
+}
+
+// ContextAttrsFromContext returns the attributes stored in ctx by WithContextAttrs.
+func ContextAttrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	attrs, _ := ctx.Value(contextAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// ContextExtractor returns attributes to add to a record, based on the logging context.
+type ContextExtractor func(ctx context.Context) []slog.Attr
+
+// ExtractContextAttrs returns an extractor for the attributes stored with WithContextAttrs.
+func ExtractContextAttrs() ContextExtractor {
+	return ContextAttrsFromContext
+}
+
+// ExtractContextValue returns an extractor reading ctx.Value(key) and exposing it
+// as an attribute named attrKey. Nothing is extracted when the value is missing.
+//
+// Example usage:
+//
+//	slogmulti.ExtractContextValue(requestIDKey{}, "request_id")
+func ExtractContextValue(key any, attrKey string) ContextExtractor {
+	return func(ctx context.Context) []slog.Attr {
+		if ctx == nil {
+			return nil
+		}
+
+		value := ctx.Value(key)
+		if value == nil {
+			return nil
+		}
+
+		return []slog.Attr{slog.Any(attrKey, value)}
+	}
+}
+
+// Ensure ContextAttrsHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*ContextAttrsHandler)(nil)
+
+// ContextAttrsHandler adds attributes extracted from the logging context to each record.
+// Keys already present on the record, or added with WithAttrs in the current group,
+// are not duplicated. When a group is set, context attributes are merged into the
+// group of the record or of WithAttrs, instead of emitting the group twice.
+type ContextAttrsHandler struct {
+	// next is the handler receiving enriched records
+	next slog.Handler
+	// group is an optional group name under which the attributes are added
+	group string
+	// extractors return the attributes to add to each record
+	extractors []ContextExtractor
+	// keys contains the attribute keys added with WithAttrs in the current scope
+	// (inside `group` when a group is set)
+	keys map[string]struct{}
+	// grouped contains the attributes added with WithAttrs under `group`, in the
+	// current scope. They are held back until Handle or WithGroup, so that context
+	// attributes are merged into the same group.
+	grouped []slog.Attr
+}
+
+// ContextAttrs creates a middleware that adds attributes extracted from the logging
+// context to each record, under an optional group. When no extractor is provided,
+// attributes stored with WithContextAttrs are used.
+//
+// Example usage:
+//
+//	logger := slog.New(
+//	    slogmulti.
+//	        Pipe(slogmulti.ContextAttrs(
+//	            "",
+//	            slogmulti.ExtractContextAttrs(),
+//	            slogmulti.ExtractContextValue(traceIDKey{}, "trace_id"),
+//	        )).
+//	        Handler(sink),
+//	)
+//
+//	ctx := slogmulti.WithContextAttrs(context.Background(), slog.String("request_id", "abcd"))
+//	logger.InfoContext(ctx, "hello") // request_id=abcd
+//
+// Args:
+//
+//	group: The group name under which attributes are added, or "" for none
+//	extractors: Functions returning the attributes to add for a given context
+//
+// Returns:
+//
+//	A middleware that adds context attributes to records
+func ContextAttrs(group string, extractors ...ContextExtractor) Middleware {
+	if len(extractors) == 0 {
+		extractors = []ContextExtractor{ExtractContextAttrs()}
+	}
+
+	return func(next slog.Handler) slog.Handler {
+		return &ContextAttrsHandler{
+			next:       next,
+			group:      group,
+			extractors: extractors,
+			keys:       map[string]struct{}{},
+		}
+	}
+}
+
+// Enabled checks if the next handler is enabled for the given log level.
+// This method implements the slog.Handler interface requirement.
+func (h *ContextAttrsHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle adds context attributes to the record and forwards it to the next handler.
+// This method implements the slog.Handler interface requirement.
+func (h *ContextAttrsHandler) Handle(ctx context.Context, r slog.Record) error {
+	extracted := []slog.Attr{}
+	for _, extractor := range h.extractors {
+		extracted = append(extracted, extractor(ctx)...)
+	}
+
+	if len(extracted) == 0 && len(h.grouped) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	// collect keys already present in the target scope
+	seen := make(map[string]struct{}, len(h.keys)+r.NumAttrs())
+	for key := range h.keys {
+		seen[key] = struct{}{}
+	}
+
+	hasGroup := false
+	r.Attrs(func(attr slog.Attr) bool {
+		if h.group == "" {
+			seen[attr.Key] = struct{}{}
+		} else if value := attr.Value.Resolve(); attr.Key == h.group && value.Kind() == slog.KindGroup {
+			// a scalar attribute with the group name is kept as-is, next to the group
+			hasGroup = true
+			for _, child := range value.Group() {
+				seen[child.Key] = struct{}{}
+			}
+		}
+		return true
+	})
+
+	attrs := make([]slog.Attr, 0, len(extracted))
+	for _, attr := range extracted {
+		if _, ok := seen[attr.Key]; ok {
+			continue
+		}
+		seen[attr.Key] = struct{}{}
+		attrs = append(attrs, attr)
+	}
+
+	if len(attrs) == 0 && len(h.grouped) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	if h.group == "" {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+		return h.next.Handle(ctx, r)
+	}
+
+	if !hasGroup && len(h.grouped) == 0 {
+		r = r.Clone()
+		r.AddAttrs(slog.Attr{Key: h.group, Value: slog.GroupValue(attrs...)})
+		return h.next.Handle(ctx, r)
+	}
+
+	// merge into the existing group, instead of emitting the same group twice
+	recordAttrs := make([]slog.Attr, 0, r.NumAttrs()+1)
+	if len(h.grouped) > 0 {
+		recordAttrs = append(recordAttrs, slog.Attr{Key: h.group, Value: slog.GroupValue(h.grouped...)})
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		if value := attr.Value.Resolve(); len(h.grouped) > 0 && attr.Key == h.group && value.Kind() == slog.KindGroup {
+			for _, child := range value.Group() {
+				recordAttrs, _ = setAttrByPath(recordAttrs, []string{h.group}, child)
+			}
+			return true
+		}
+
+		recordAttrs = append(recordAttrs, attr)
+		return true
+	})
+	for _, attr := range attrs {
+		recordAttrs, _ = setAttrByPath(recordAttrs, []string{h.group}, attr)
+	}
+
+	return h.next.Handle(ctx, recordWithAttrs(r, recordAttrs))
+}
+
+// WithAttrs creates a new ContextAttrsHandler with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *ContextAttrsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	keys := make(map[string]struct{}, len(h.keys)+len(attrs))
+	for key := range h.keys {
+		keys[key] = struct{}{}
+	}
+
+	grouped := h.grouped
+	forwarded := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		if h.group == "" {
+			keys[attr.Key] = struct{}{}
+		} else if value := attr.Value.Resolve(); attr.Key == h.group && value.Kind() == slog.KindGroup {
+			for _, child := range value.Group() {
+				keys[child.Key] = struct{}{}
+				grouped, _ = setAttrByPath(grouped, nil, child)
+			}
+			continue
+		}
+
+		forwarded = append(forwarded, attr)
+	}
+
+	next := h.next
+	if len(forwarded) > 0 {
+		next = next.WithAttrs(forwarded)
+	}
+
+	return &ContextAttrsHandler{
+		next:       next,
+		group:      h.group,
+		extractors: h.extractors,
+		keys:       keys,
+		grouped:    grouped,
+	}
+}
+
+// WithGroup creates a new ContextAttrsHandler with a group name.
+// This method implements the slog.Handler interface requirement.
+//
+// Attributes added with WithAttrs before the group belong to another scope,
+// so they are not considered as duplicates anymore.
+func (h *ContextAttrsHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	// the held back group belongs to the current scope
+	next := h.next
+	if len(h.grouped) > 0 {
+		next = next.WithAttrs([]slog.Attr{{Key: h.group, Value: slog.GroupValue(h.grouped...)}})
+	}
+
+	return &ContextAttrsHandler{
+		next:       next.WithGroup(name),
+		group:      h.group,
+		extractors: h.extractors,
+		keys:       map[string]struct{}{},
+	}
+}
+
+// Flush flushes the next handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *ContextAttrsHandler) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Close closes the next handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *ContextAttrsHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Inspect describes the ContextAttrs middleware and the next handler.
+// This method implements the Inspector interface.
+func (h *ContextAttrsHandler) Inspect() HandlerNode {
+	node := inspectNode("ContextAttrs", nil, nil, h.next)
+	node.Name = h.group
+	return node
+}
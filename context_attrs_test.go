@@ -0,0 +1,113 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTraceIDKey struct{}
+
+func TestContextAttrs(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(ContextAttrs(
+		"",
+		ExtractContextAttrs(),
+		ExtractContextValue(testTraceIDKey{}, "trace_id"),
+	))
+
+	ctx := WithContextAttrs(context.Background(), slog.String("request_id", "r-1"))
+	ctx = WithContextAttrs(ctx, slog.String("user_id", "u-1"))
+	ctx = context.WithValue(ctx, testTraceIDKey{}, "t-1")
+
+	logger.InfoContext(ctx, "hello")
+	is.Equal(map[string]any{
+		"level":      "INFO",
+		"msg":        "hello",
+		"request_id": "r-1",
+		"user_id":    "u-1",
+		"trace_id":   "t-1",
+	}, decodeJSONLine(t, buf))
+
+	// keys already on the record are not duplicated
+	logger.With("user_id", "u-2").InfoContext(ctx, "hello", "request_id", "r-2")
+	is.Equal(`{"level":"INFO","msg":"hello","user_id":"u-2","request_id":"r-2","trace_id":"t-1"}`+"\n", buf.String())
+	buf.Reset()
+
+	// no context attributes
+	logger.Info("hello")
+	is.Equal(map[string]any{
+		"level": "INFO",
+		"msg":   "hello",
+	}, decodeJSONLine(t, buf))
+}
+
+func TestContextAttrsGroup(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(ContextAttrs("ctx"))
+
+	ctx := WithContextAttrs(context.Background(), slog.String("request_id", "r-1"), slog.String("user_id", "u-1"))
+
+	logger.InfoContext(ctx, "hello")
+	is.Equal(map[string]any{
+		"level": "INFO",
+		"msg":   "hello",
+		"ctx":   map[string]any{"request_id": "r-1", "user_id": "u-1"},
+	}, decodeJSONLine(t, buf))
+
+	logger.InfoContext(ctx, "hello", slog.Group("ctx", slog.String("user_id", "u-2")))
+	is.Equal(map[string]any{
+		"level": "INFO",
+		"msg":   "hello",
+		"ctx":   map[string]any{"request_id": "r-1", "user_id": "u-2"},
+	}, decodeJSONLine(t, buf))
+}
+
+func TestContextAttrsGroupWithAttrs(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(ContextAttrs("ctx"))
+	ctx := WithContextAttrs(context.Background(), slog.String("request_id", "r-1"), slog.String("user_id", "u-1"))
+
+	// context attributes are merged into the group added with WithAttrs
+	logger.With(slog.Group("ctx", slog.String("tenant", "acme"), slog.String("user_id", "u-2")), "env", "prod").InfoContext(ctx, "hello")
+	is.Equal(`{"level":"INFO","msg":"hello","env":"prod","ctx":{"tenant":"acme","user_id":"u-2","request_id":"r-1"}}`+"\n", buf.String())
+	buf.Reset()
+
+	// and with the group of the record
+	logger.With(slog.Group("ctx", slog.String("tenant", "acme"))).InfoContext(ctx, "hello", slog.Group("ctx", slog.String("user_id", "u-3")))
+	is.Equal(`{"level":"INFO","msg":"hello","ctx":{"tenant":"acme","user_id":"u-3","request_id":"r-1"}}`+"\n", buf.String())
+	buf.Reset()
+
+	// without context attributes, the group is kept
+	logger.With(slog.Group("ctx", slog.String("tenant", "acme"))).Info("hello")
+	is.Equal(`{"level":"INFO","msg":"hello","ctx":{"tenant":"acme"}}`+"\n", buf.String())
+	buf.Reset()
+
+	// after WithGroup, the group belongs to the parent scope
+	logger.With(slog.Group("ctx", slog.String("tenant", "acme"))).WithGroup("http").InfoContext(ctx, "hello")
+	is.Equal(`{"level":"INFO","msg":"hello","ctx":{"tenant":"acme"},"http":{"ctx":{"request_id":"r-1","user_id":"u-1"}}}`+"\n", buf.String())
+}
+
+func TestContextAttrsGroupNameOfScalar(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(ContextAttrs("ctx"))
+	ctx := WithContextAttrs(context.Background(), slog.String("request_id", "r-1"))
+
+	// the scalar is kept, next to the group
+	is.NotPanics(func() { logger.InfoContext(ctx, "record", "ctx", "scalar") })
+	is.Contains(buf.String(), `"msg":"record","ctx":"scalar","ctx":{"request_id":"r-1"}}`)
+
+	buf.Reset()
+	is.NotPanics(func() { logger.With("ctx", "scalar").InfoContext(ctx, "with attrs") })
+	is.Contains(buf.String(), `"msg":"with attrs","ctx":"scalar","ctx":{"request_id":"r-1"}}`)
+}
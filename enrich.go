@@ -0,0 +1,242 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var processStart = time.Now()
+
+var processAttrs = sync.OnceValue(func() []slog.Attr {
+	attrs := []slog.Attr{}
+
+	if hostname, err := os.Hostname(); err == nil {
+		attrs = append(attrs, slog.String("hostname", hostname))
+	}
+
+	attrs = append(
+		attrs,
+		slog.Int("pid", os.Getpid()),
+		slog.String("go_version", runtime.Version()),
+	)
+
+	return attrs
+})
+
+var buildAttrs = sync.OnceValue(func() []slog.Attr {
+	attrs := []slog.Attr{}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return attrs
+	}
+
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		attrs = append(attrs, slog.String("version", info.Main.Version))
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			attrs = append(attrs, slog.String("vcs_revision", setting.Value))
+		case "vcs.time":
+			attrs = append(attrs, slog.String("vcs_time", setting.Value))
+		case "vcs.modified":
+			attrs = append(attrs, slog.Bool("vcs_modified", setting.Value == "true"))
+		}
+	}
+
+	return attrs
+})
+
+// ProcessAttrs returns attributes describing the current process: hostname, pid and go_version.
+// They are collected once.
+func ProcessAttrs() []slog.Attr {
+	return append([]slog.Attr{}, processAttrs()...)
+}
+
+// BuildAttrs returns attributes describing the current build, read from runtime/debug.ReadBuildInfo:
+// version (main module version), vcs_revision, vcs_time and vcs_modified. They are collected once.
+// Attributes are omitted when the information is not available.
+func BuildAttrs() []slog.Attr {
+	return append([]slog.Attr{}, buildAttrs()...)
+}
+
+// GoroutineCount returns an extractor adding the number of goroutines to each record.
+func GoroutineCount() ContextExtractor {
+	return func(ctx context.Context) []slog.Attr {
+		return []slog.Attr{slog.Int("goroutines", runtime.NumGoroutine())}
+	}
+}
+
+// Uptime returns an extractor adding the process uptime to each record.
+func Uptime() ContextExtractor {
+	return func(ctx context.Context) []slog.Attr {
+		return []slog.Attr{slog.Duration("uptime", time.Since(processStart))}
+	}
+}
+
+// EnrichOption configures the Enrich middleware.
+type EnrichOption struct {
+	// ServiceName is added as a "service" attribute, when not empty.
+	ServiceName string
+	// ServiceVersion is added as a "version" attribute, when not empty.
+	// It overrides the version read from build info.
+	ServiceVersion string
+
+	// DisableProcessAttrs removes the attributes returned by ProcessAttrs.
+	DisableProcessAttrs bool
+	// DisableBuildAttrs removes the attributes returned by BuildAttrs.
+	DisableBuildAttrs bool
+
+	// Attrs are additional constant attributes.
+	Attrs []slog.Attr
+	// Dynamic attributes are computed on every record (eg: GoroutineCount, Uptime).
+	Dynamic []ContextExtractor
+}
+
+// Enrich creates a middleware adding process and build metadata to each record.
+//
+// Constant attributes (service name, version, hostname, pid, vcs revision...) are
+// collected once and added through WithAttrs, so that they are not rebuilt on every
+// record. Dynamic attributes are computed on every record and added at the top
+// level of the record, even after WithGroup, unless the record already carries a
+// top-level attribute with the same key.
+//
+// Example usage:
+//
+//	logger := slog.New(
+//	    slogmulti.
+//	        Pipe(slogmulti.Enrich(slogmulti.EnrichOption{
+//	            ServiceName: "api",
+//	            Dynamic:     []slogmulti.ContextExtractor{slogmulti.GoroutineCount(), slogmulti.Uptime()},
+//	        })).
+//	        Handler(sink),
+//	)
+//
+// Args:
+//
+//	opts: The enrichment configuration
+//
+// Returns:
+//
+//	A middleware adding metadata to records
+func Enrich(opts EnrichOption) Middleware {
+	static := []slog.Attr{}
+	if opts.ServiceName != "" {
+		static = append(static, slog.String("service", opts.ServiceName))
+	}
+	if !opts.DisableProcessAttrs {
+		static = append(static, processAttrs()...)
+	}
+	if !opts.DisableBuildAttrs {
+		for _, attr := range buildAttrs() {
+			if attr.Key == "version" && opts.ServiceVersion != "" {
+				continue
+			}
+			static = append(static, attr)
+		}
+	}
+	if opts.ServiceVersion != "" {
+		static = append(static, slog.String("version", opts.ServiceVersion))
+	}
+	static = append(static, opts.Attrs...)
+
+	return func(next slog.Handler) slog.Handler {
+		if len(static) > 0 {
+			next = next.WithAttrs(static)
+		}
+
+		return &EnrichHandler{
+			next:    next,
+			dynamic: opts.Dynamic,
+			root:    newRootAttrs(next),
+		}
+	}
+}
+
+// Ensure EnrichHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*EnrichHandler)(nil)
+
+// EnrichHandler adds dynamic process metadata to records before forwarding them
+// to the next handler. Constant metadata is added to the next handler when the
+// middleware is built. See Enrich.
+type EnrichHandler struct {
+	// next is the handler receiving records, with constant metadata
+	next slog.Handler
+	// dynamic extractors are evaluated on every record
+	dynamic []ContextExtractor
+	// root adds dynamic attributes outside of the current groups
+	root rootAttrs
+}
+
+// Enabled checks if the next handler is enabled for the given log level.
+// This method implements the slog.Handler interface requirement.
+func (h *EnrichHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle adds dynamic attributes to the record and forwards it to the next handler.
+// This method implements the slog.Handler interface requirement.
+func (h *EnrichHandler) Handle(ctx context.Context, r slog.Record) error {
+	if len(h.dynamic) == 0 {
+		return h.next.Handle(ctx, r)
+	}
+
+	attrs := []slog.Attr{}
+	for _, extractor := range h.dynamic {
+		attrs = append(attrs, extractor(ctx)...)
+	}
+
+	return h.root.handle(ctx, h.next, r, attrs)
+}
+
+// WithAttrs creates a new EnrichHandler with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *EnrichHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := h.next.WithAttrs(attrs)
+
+	return &EnrichHandler{
+		next:    next,
+		dynamic: h.dynamic,
+		root:    h.root.withAttrs(next, attrs),
+	}
+}
+
+// WithGroup creates a new EnrichHandler with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *EnrichHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	return &EnrichHandler{
+		next:    h.next.WithGroup(name),
+		dynamic: h.dynamic,
+		root:    h.root.withGroup(name),
+	}
+}
+
+// Flush flushes the next handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *EnrichHandler) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Close closes the next handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *EnrichHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Inspect describes the Enrich middleware and the next handler.
+// This method implements the Inspector interface.
+func (h *EnrichHandler) Inspect() HandlerNode {
+	return inspectNode("Enrich", h.root.groups, nil, h.next)
+}
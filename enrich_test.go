@@ -0,0 +1,99 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnrich(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	calls := 0
+	logger, buf := newJSONTestLogger(Enrich(EnrichOption{
+		ServiceName:    "api",
+		ServiceVersion: "v1.2.3",
+		Attrs:          []slog.Attr{slog.String("env", "test")},
+		Dynamic: []ContextExtractor{
+			func(ctx context.Context) []slog.Attr {
+				calls++
+				return []slog.Attr{slog.Int("call", calls)}
+			},
+		},
+	}))
+
+	hostname, _ := os.Hostname()
+
+	logger.Info("first")
+	output := decodeJSONLine(t, buf)
+	is.Equal("api", output["service"])
+	is.Equal("v1.2.3", output["version"])
+	is.Equal("test", output["env"])
+	is.Equal(hostname, output["hostname"])
+	is.Equal(float64(os.Getpid()), output["pid"])
+	is.Equal(runtime.Version(), output["go_version"])
+	is.Equal(float64(1), output["call"])
+
+	logger.WithGroup("group").Info("second", "foo", "bar")
+	output = decodeJSONLine(t, buf)
+	is.Equal("api", output["service"])
+	is.Equal(float64(2), output["call"])
+	is.Equal(map[string]any{"foo": "bar"}, output["group"])
+}
+
+func TestEnrichHandler(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	sink := &noopHandler{}
+
+	// without dynamic attributes, the middleware is still inspectable
+	handler := Enrich(EnrichOption{DisableProcessAttrs: true, DisableBuildAttrs: true})(sink)
+	is.IsType(&EnrichHandler{}, handler)
+	is.Equal("Enrich\n└── *slogmulti.noopHandler\n", Describe(handler))
+
+	logger, buf := newJSONTestLogger(Enrich(EnrichOption{
+		DisableProcessAttrs: true,
+		DisableBuildAttrs:   true,
+		Dynamic: []ContextExtractor{
+			func(ctx context.Context) []slog.Attr {
+				return []slog.Attr{slog.String("region", "eu")}
+			},
+		},
+	}))
+
+	logger.With("env", "prod").WithGroup("http").With("method", "GET").Info("hello", "status", 200)
+	is.Equal(map[string]any{
+		"level":  "INFO",
+		"msg":    "hello",
+		"env":    "prod",
+		"region": "eu",
+		"http":   map[string]any{"method": "GET", "status": float64(200)},
+	}, decodeJSONLine(t, buf))
+
+	// existing keys are not duplicated
+	logger.Info("hello", "region", "us")
+	is.Equal(`{"level":"INFO","msg":"hello","region":"us"}`+"\n", buf.String())
+}
+
+func TestEnrichDisabled(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(Enrich(EnrichOption{
+		DisableProcessAttrs: true,
+		DisableBuildAttrs:   true,
+		Dynamic:             []ContextExtractor{GoroutineCount(), Uptime()},
+	}))
+
+	logger.Info("hello")
+	output := decodeJSONLine(t, buf)
+	is.Len(output, 4)
+	is.Contains(output, "goroutines")
+	is.Contains(output, "uptime")
+}
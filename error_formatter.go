@@ -0,0 +1,395 @@
+package slogmulti
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	slogcommon "github.com/samber/slog-common"
+)
+
+// ErrorAttrer can be implemented by errors to expose structured fields.
+// It is similar to slog.LogValuer, but does not change how the error is
+// rendered by other handlers.
+type ErrorAttrer interface {
+	ErrorAttrs() []slog.Attr
+}
+
+// ErrorFormatterOption configures the ErrorFormatter middleware.
+type ErrorFormatterOption struct {
+	// Paths restricts error lookups to the given dotted attribute paths (eg: "error", "http.err").
+	// By default, error values are formatted under any key, at any depth.
+	Paths []string
+
+	// StackTraceLevel is the minimum level at which a stack trace of the logging site is captured.
+	// Default: slog.LevelError.
+	StackTraceLevel slog.Leveler
+	// DisableStackTrace disables stack trace capture.
+	DisableStackTrace bool
+	// MaxStackTraceDepth is the maximum number of frames in a stack trace. Default: 32.
+	MaxStackTraceDepth int
+
+	// MaxCauses is the maximum number of causes unwrapped from an error. Default: 32.
+	MaxCauses int
+}
+
+// ErrorFormatter creates a middleware that turns `error` values into structured groups:
+//
+//	error.type       - the Go type of the error
+//	error.message    - the error message
+//	error.causes     - the errors unwrapped from `%w` and errors.Join chains
+//	error.fields     - the attributes of errors implementing ErrorAttrer or slog.LogValuer
+//	error.stacktrace - the stack trace of the logging site, for error-level records
+//
+// Errors added with WithAttrs are formatted once, without stack trace.
+//
+// Example usage:
+//
+//	logger := slog.New(
+//	    slogmulti.
+//	        Pipe(slogmulti.ErrorFormatter(slogmulti.ErrorFormatterOption{})).
+//	        Handler(sink),
+//	)
+//
+//	logger.Error("could not fetch user", "error", fmt.Errorf("fetch: %w", err))
+//
+// Args:
+//
+//	opts: The formatter configuration
+//
+// Returns:
+//
+//	A middleware formatting errors before forwarding records to the next handler
+func ErrorFormatter(opts ErrorFormatterOption) Middleware {
+	if opts.StackTraceLevel == nil {
+		opts.StackTraceLevel = slog.LevelError
+	}
+	if opts.MaxStackTraceDepth <= 0 {
+		opts.MaxStackTraceDepth = 32
+	}
+	if opts.MaxCauses <= 0 {
+		opts.MaxCauses = 32
+	}
+
+	paths := make(map[string]struct{}, len(opts.Paths))
+	for _, path := range opts.Paths {
+		paths[path] = struct{}{}
+	}
+
+	return func(next slog.Handler) slog.Handler {
+		return &ErrorFormatterHandler{
+			next:   next,
+			opts:   opts,
+			paths:  paths,
+			groups: []string{},
+		}
+	}
+}
+
+// Ensure ErrorFormatterHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*ErrorFormatterHandler)(nil)
+
+// ErrorFormatterHandler formats `error` attributes before forwarding records to the next handler.
+type ErrorFormatterHandler struct {
+	// next is the handler receiving formatted records
+	next slog.Handler
+	// opts is the formatter configuration
+	opts ErrorFormatterOption
+	// paths is the set of attribute paths to inspect, or all paths when empty
+	paths map[string]struct{}
+	// groups tracks the current group hierarchy, to match paths
+	groups []string
+}
+
+// Enabled checks if the next handler is enabled for the given log level.
+// This method implements the slog.Handler interface requirement.
+func (h *ErrorFormatterHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle formats errors found in the record and forwards it to the next handler.
+// This method implements the slog.Handler interface requirement.
+func (h *ErrorFormatterHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+
+	var stacktrace []string
+	captured := false
+	captureStack := func() []string {
+		if !captured {
+			captured = true
+			if !h.opts.DisableStackTrace && r.Level >= h.opts.StackTraceLevel.Level() {
+				stacktrace = captureStackTrace(r.PC, h.opts.MaxStackTraceDepth)
+			}
+		}
+		return stacktrace
+	}
+
+	attrs, changed := h.formatAttrs(attrs, h.groups, captureStack)
+	if !changed {
+		return h.next.Handle(ctx, r)
+	}
+
+	return h.next.Handle(ctx, recordWithAttrs(r, attrs))
+}
+
+// WithAttrs creates a new ErrorFormatterHandler, with errors of the attributes already formatted.
+// This method implements the slog.Handler interface requirement.
+func (h *ErrorFormatterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs, _ = h.formatAttrs(slices.Clone(attrs), h.groups, func() []string { return nil })
+
+	return &ErrorFormatterHandler{
+		next:   h.next.WithAttrs(attrs),
+		opts:   h.opts,
+		paths:  h.paths,
+		groups: h.groups,
+	}
+}
+
+// WithGroup creates a new ErrorFormatterHandler with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *ErrorFormatterHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	return &ErrorFormatterHandler{
+		next:   h.next.WithGroup(name),
+		opts:   h.opts,
+		paths:  h.paths,
+		groups: append(slices.Clone(h.groups), name),
+	}
+}
+
+// formatAttrs replaces error values in place. The caller must own the attrs slice.
+func (h *ErrorFormatterHandler) formatAttrs(attrs []slog.Attr, groups []string, stacktrace func() []string) ([]slog.Attr, bool) {
+	changed := false
+
+	for i, attr := range attrs {
+		if err, ok := attrError(attr); ok {
+			if h.matchPath(groups, attr.Key) {
+				attrs[i] = slog.Attr{Key: attr.Key, Value: h.formatError(err, stacktrace())}
+				changed = true
+			}
+			continue
+		}
+
+		value := attr.Value.Resolve()
+		if value.Kind() != slog.KindGroup {
+			continue
+		}
+
+		path := append(slices.Clone(groups), attr.Key)
+		children, childChanged := h.formatAttrs(slices.Clone(value.Group()), path, stacktrace)
+		if childChanged {
+			attrs[i] = slog.Attr{Key: attr.Key, Value: slog.GroupValue(children...)}
+			changed = true
+		}
+	}
+
+	return attrs, changed
+}
+
+func (h *ErrorFormatterHandler) matchPath(groups []string, key string) bool {
+	if len(h.paths) == 0 {
+		return true
+	}
+
+	path := key
+	if len(groups) > 0 {
+		path = strings.Join(groups, ".") + "." + key
+	}
+
+	_, ok := h.paths[path]
+	return ok
+}
+
+func (h *ErrorFormatterHandler) formatError(err error, stacktrace []string) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("type", reflect.TypeOf(err).String()),
+		slog.String("message", errorMessage(err)),
+	}
+
+	if fields := errorFields(err); len(fields) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(fields...)})
+	}
+
+	causes := unwrapErrors(err, h.opts.MaxCauses)
+	if len(causes) > 0 {
+		output := make([]map[string]any, 0, len(causes))
+		for _, cause := range causes {
+			item := map[string]any{
+				"type":    reflect.TypeOf(cause).String(),
+				"message": errorMessage(cause),
+			}
+			if fields := errorFields(cause); len(fields) > 0 {
+				item["fields"] = slogcommon.AttrsToMap(fields...)
+			}
+			output = append(output, item)
+		}
+		attrs = append(attrs, slog.Any("causes", output))
+	}
+
+	if len(stacktrace) > 0 {
+		attrs = append(attrs, slog.Any("stacktrace", stacktrace))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// attrError returns the error held by the attribute, without resolving LogValuers,
+// since an error might implement slog.LogValuer.
+func attrError(attr slog.Attr) (error, bool) {
+	kind := attr.Value.Kind()
+	if kind != slog.KindAny && kind != slog.KindLogValuer {
+		return nil, false
+	}
+
+	err, ok := attr.Value.Any().(error)
+	return err, ok && err != nil
+}
+
+// errorMessage returns the message of the error. Like slog, a typed-nil error
+// (eg: (*MyError)(nil)) whose Error method panics is rendered as "<nil>".
+func errorMessage(err error) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			if isNilError(err) {
+				msg = "<nil>"
+				return
+			}
+			msg = fmt.Sprintf("!PANIC: %v", r)
+		}
+	}()
+
+	return err.Error()
+}
+
+// isNilError returns true for typed-nil errors, such as (*MyError)(nil).
+func isNilError(err error) bool {
+	v := reflect.ValueOf(err)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// errorFields returns the structured fields exposed by an error.
+func errorFields(err error) []slog.Attr {
+	if isNilError(err) {
+		return nil
+	}
+
+	if attrer, ok := err.(ErrorAttrer); ok {
+		return attrer.ErrorAttrs()
+	}
+
+	if valuer, ok := err.(slog.LogValuer); ok {
+		value := slog.AnyValue(valuer).Resolve()
+		if value.Kind() == slog.KindGroup {
+			return value.Group()
+		}
+		return []slog.Attr{{Key: "value", Value: value}}
+	}
+
+	return nil
+}
+
+// unwrapErrors returns the errors wrapped by err (depth-first), excluding err itself.
+// Both `Unwrap() error` and `Unwrap() []error` (errors.Join) are supported.
+func unwrapErrors(err error, limit int) []error {
+	causes := []error{}
+
+	var walk func(error)
+	walk = func(err error) {
+		if isNilError(err) {
+			return
+		}
+
+		var children []error
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			children = e.Unwrap()
+		case interface{ Unwrap() error }:
+			children = []error{e.Unwrap()}
+		}
+
+		for _, child := range children {
+			if child == nil || len(causes) >= limit {
+				continue
+			}
+			causes = append(causes, child)
+			walk(child)
+		}
+	}
+	walk(err)
+
+	return causes
+}
+
+// captureStackTrace returns the call stack, starting at the logging site.
+// The logging site is identified by the record PC. When it cannot be found in
+// the current stack (eg: asynchronous handler), frames from log/slog and
+// slog-multi are skipped.
+func captureStackTrace(pc uintptr, depth int) []string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	pcs = pcs[:n]
+
+	start := -1
+	if pc != 0 {
+		start = slices.Index(pcs, pc)
+	}
+
+	frames := runtime.CallersFrames(pcs[max(start, 0):])
+	output := make([]string, 0, depth)
+	skipping := start < 0
+
+	for len(output) < depth {
+		frame, more := frames.Next()
+
+		if skipping && (strings.HasPrefix(frame.Function, "log/slog.") || strings.HasPrefix(frame.Function, "github.com/samber/slog-multi.")) {
+			if !more {
+				break
+			}
+			continue
+		}
+		skipping = false
+
+		output = append(output, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+
+		if !more {
+			break
+		}
+	}
+
+	// we only found frames from logging packages
+	if len(output) == 0 && start < 0 {
+		return nil
+	}
+
+	return output
+}
+
+// Flush flushes the next handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *ErrorFormatterHandler) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Close closes the next handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *ErrorFormatterHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Inspect describes the ErrorFormatter middleware and the next handler.
+// This method implements the Inspector interface.
+func (h *ErrorFormatterHandler) Inspect() HandlerNode {
+	return inspectNode("ErrorFormatter", h.groups, nil, h.next)
+}
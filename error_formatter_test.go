@@ -0,0 +1,128 @@
+package slogmulti
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNotFoundError struct {
+	id string
+}
+
+func (e *testNotFoundError) Error() string { return "not found: " + e.id }
+
+func (e *testNotFoundError) ErrorAttrs() []slog.Attr {
+	return []slog.Attr{slog.String("id", e.id)}
+}
+
+func TestErrorFormatter(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(ErrorFormatter(ErrorFormatterOption{}))
+
+	root := &testNotFoundError{id: "42"}
+	err := fmt.Errorf("fetch user: %w", errors.Join(root, errors.New("timeout")))
+
+	logger.Warn("failed", "err", err, slog.Group("http", slog.Any("cause", root)), "count", 1)
+
+	is.Equal(map[string]any{
+		"level": "WARN",
+		"msg":   "failed",
+		"err": map[string]any{
+			"type":    "*fmt.wrapError",
+			"message": "fetch user: not found: 42\ntimeout",
+			"causes": []any{
+				map[string]any{"type": "*errors.joinError", "message": "not found: 42\ntimeout"},
+				map[string]any{"type": "*slogmulti.testNotFoundError", "message": "not found: 42", "fields": map[string]any{"id": "42"}},
+				map[string]any{"type": "*errors.errorString", "message": "timeout"},
+			},
+		},
+		"http": map[string]any{
+			"cause": map[string]any{
+				"type":    "*slogmulti.testNotFoundError",
+				"message": "not found: 42",
+				"fields":  map[string]any{"id": "42"},
+			},
+		},
+		"count": float64(1),
+	}, decodeJSONLine(t, buf))
+}
+
+func TestErrorFormatterTypedNil(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(ErrorFormatter(ErrorFormatterOption{}))
+
+	var err *testNotFoundError
+	logger.Warn("failed", "err", err, "wrapped", fmt.Errorf("fetch user: %w", err))
+
+	is.Equal(map[string]any{
+		"level": "WARN",
+		"msg":   "failed",
+		"err": map[string]any{
+			"type":    "*slogmulti.testNotFoundError",
+			"message": "<nil>",
+		},
+		"wrapped": map[string]any{
+			"type":    "*fmt.wrapError",
+			"message": "fetch user: <nil>",
+			"causes": []any{
+				map[string]any{"type": "*slogmulti.testNotFoundError", "message": "<nil>"},
+			},
+		},
+	}, decodeJSONLine(t, buf))
+}
+
+func TestErrorFormatterStackTrace(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(ErrorFormatter(ErrorFormatterOption{}))
+
+	logger.Error("failed", "error", assert.AnError)
+	output := decodeJSONLine(t, buf)
+
+	stacktrace, ok := output["error"].(map[string]any)["stacktrace"].([]any)
+	is.True(ok)
+	is.NotEmpty(stacktrace)
+	is.True(strings.HasPrefix(stacktrace[0].(string), "github.com/samber/slog-multi.TestErrorFormatterStackTrace "), stacktrace[0])
+
+	// no stack trace below the configured level
+	logger.Warn("failed", "error", assert.AnError)
+	output = decodeJSONLine(t, buf)
+	is.NotContains(output["error"], "stacktrace")
+}
+
+func TestErrorFormatterPaths(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(ErrorFormatter(ErrorFormatterOption{
+		Paths:             []string{"request.error"},
+		DisableStackTrace: true,
+	}))
+
+	logger.
+		With("error", assert.AnError).
+		WithGroup("request").
+		Error("failed", "error", assert.AnError)
+
+	is.Equal(map[string]any{
+		"level": "ERROR",
+		"msg":   "failed",
+		"error": assert.AnError.Error(),
+		"request": map[string]any{
+			"error": map[string]any{
+				"type":    "*errors.errorString",
+				"message": assert.AnError.Error(),
+			},
+		},
+	}, decodeJSONLine(t, buf))
+}
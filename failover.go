@@ -149,3 +149,37 @@ func (h *FailoverHandler) WithGroup(name string) slog.Handler {
 	})
 	return Failover()(handers...)
 }
+
+// Flush flushes all child handlers implementing Flusher.
+// This method implements the Flusher interface.
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//
+// Returns:
+//
+//	The joined errors of the child handlers
+func (h *FailoverHandler) Flush(ctx context.Context) error {
+	return flushHandlers(ctx, h.handlers...)
+}
+
+// Close closes all child handlers implementing Closer.
+// This method implements the Closer interface.
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//
+// Returns:
+//
+//	The joined errors of the child handlers
+func (h *FailoverHandler) Close(ctx context.Context) error {
+	return closeHandlers(ctx, h.handlers...)
+}
+
+// Inspect describes the Failover handler and its children, by priority.
+// This method implements the Inspector interface.
+func (h *FailoverHandler) Inspect() HandlerNode {
+	return inspectNode("Failover", nil, nil, h.handlers...)
+}
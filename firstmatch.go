@@ -18,11 +18,16 @@ type FirstMatchHandler struct {
 func FirstMatch(handlers ...*RoutableHandler) *FirstMatchHandler {
 	return &FirstMatchHandler{handlers: lo.Map(handlers, func(h *RoutableHandler, _ int) *RoutableHandler {
 		return &RoutableHandler{
+			name:           h.name,
 			predicates:     h.predicates,
+			matchers:       h.matchers,
 			handler:        h.handler,
 			groups:         slices.Clone(h.groups),
 			attrs:          slices.Clone(h.attrs),
 			skipPredicates: true, // prevent double matching
+			priority:       h.priority,
+			continues:      h.continues,
+			drop:           h.drop,
 		}
 	})}
 }
@@ -94,3 +99,40 @@ func (h *FirstMatchHandler) WithGroup(name string) slog.Handler {
 func newFirstMatch(handlers ...*RoutableHandler) *FirstMatchHandler {
 	return &FirstMatchHandler{handlers: handlers}
 }
+
+// Flush flushes all child handlers implementing Flusher.
+// This method implements the Flusher interface.
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//
+// Returns:
+//
+//	The joined errors of the child handlers
+func (h *FirstMatchHandler) Flush(ctx context.Context) error {
+	return flushHandlers(ctx, h.handlers...)
+}
+
+// Close closes all child handlers implementing Closer.
+// This method implements the Closer interface.
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//
+// Returns:
+//
+//	The joined errors of the child handlers
+func (h *FirstMatchHandler) Close(ctx context.Context) error {
+	return closeHandlers(ctx, h.handlers...)
+}
+
+// Inspect describes the FirstMatch handler and its routes, in matching order.
+// This method implements the Inspector interface.
+func (h *FirstMatchHandler) Inspect() HandlerNode {
+	return HandlerNode{
+		Type:     "FirstMatch",
+		Children: inspectHandlers(h.handlers...),
+	}
+}
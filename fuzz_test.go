@@ -317,3 +317,26 @@ func FuzzRouterPredicates(f *testing.F) {
 		AttrKindIs("key", slog.KindString)(ctx, r)
 	})
 }
+
+func FuzzRouterIndex(f *testing.F) {
+	f.Add(0, "hello", "tenant-0", int64(3), true, false)
+	f.Add(8, "drop", "tenant-5", int64(0), false, true)
+	f.Add(4, "func", "tenant-7", int64(3), true, true)
+	f.Add(-4, "", "", int64(-1), false, false)
+
+	f.Fuzz(func(t *testing.T, levelInt int, msg string, tenant string, count int64, flag bool, firstMatch bool) {
+		indexedLog := []string{}
+		linearLog := []string{}
+
+		indexed := buildIndexTestRouter(&indexedLog, firstMatch).Handler().(*RouterHandler)
+		linear := buildIndexTestRouter(&linearLog, firstMatch).Handler().(*RouterHandler)
+		linear.index = nil
+
+		r := slog.NewRecord(time.Now(), slog.Level(levelInt), msg, 0)
+		r.AddAttrs(slog.String("tenant", tenant), slog.Int64("count", count), slog.Bool("flag", flag))
+
+		assert.NoError(t, indexed.Handle(context.Background(), r))
+		assert.NoError(t, linear.Handle(context.Background(), r))
+		assert.Equal(t, linearLog, indexedLog)
+	})
+}
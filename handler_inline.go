@@ -7,6 +7,8 @@ import (
 )
 
 // NewInlineHandler is a shortcut to a handler that implements all methods.
+// attrs are the attributes added with WithAttrs: the ones added after WithGroup
+// are nested under the groups opened before them (see ResolveRecord).
 func NewInlineHandler(
 	enabledFunc func(ctx context.Context, groups []string, attrs []slog.Attr, level slog.Level) bool,
 	handleFunc func(ctx context.Context, groups []string, attrs []slog.Attr, record slog.Record) error,
@@ -47,9 +49,10 @@ func (h *InlineHandler) Handle(ctx context.Context, record slog.Record) error {
 
 // Implements slog.Handler
 func (h *InlineHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	newAttrs := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
-	newAttrs = append(newAttrs, h.attrs...)
-	newAttrs = append(newAttrs, attrs...)
+	newAttrs := h.attrs
+	if len(attrs) > 0 {
+		newAttrs = appendGroupedAttrs(h.attrs, h.groups, attrs)
+	}
 
 	return &InlineHandler{
 		groups:      h.groups,
@@ -76,3 +79,8 @@ func (h *InlineHandler) WithGroup(name string) slog.Handler {
 		handleFunc:  h.handleFunc,
 	}
 }
+
+// Implements slogmulti.Inspector
+func (h *InlineHandler) Inspect() HandlerNode {
+	return inspectNode("InlineHandler", h.groups, h.attrs)
+}
@@ -7,6 +7,8 @@ import (
 )
 
 // NewHandleInlineHandler is a shortcut to a middleware that implements only the `Handle` method.
+// attrs are the attributes added with WithAttrs: the ones added after WithGroup
+// are nested under the groups opened before them (see ResolveRecord).
 func NewHandleInlineHandler(handleFunc func(ctx context.Context, groups []string, attrs []slog.Attr, record slog.Record) error) slog.Handler {
 	return &HandleInlineHandler{
 		groups:     []string{},
@@ -35,9 +37,10 @@ func (h *HandleInlineHandler) Handle(ctx context.Context, record slog.Record) er
 
 // Implements slog.Handler
 func (h *HandleInlineHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	newAttrs := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
-	newAttrs = append(newAttrs, h.attrs...)
-	newAttrs = append(newAttrs, attrs...)
+	newAttrs := h.attrs
+	if len(attrs) > 0 {
+		newAttrs = appendGroupedAttrs(h.attrs, h.groups, attrs)
+	}
 
 	return &HandleInlineHandler{
 		groups:     h.groups,
@@ -62,3 +65,8 @@ func (h *HandleInlineHandler) WithGroup(name string) slog.Handler {
 		handleFunc: h.handleFunc,
 	}
 }
+
+// Implements slogmulti.Inspector
+func (h *HandleInlineHandler) Inspect() HandlerNode {
+	return inspectNode("HandleInlineHandler", h.groups, h.attrs)
+}
@@ -0,0 +1,125 @@
+package slogmulti
+
+import (
+	"context"
+	"fmt"
+
+	"log/slog"
+
+	slogcommon "github.com/samber/slog-common"
+	"github.com/samber/slog-multi/internal/slogattr"
+)
+
+// NewInlineHandlerWithOptions is a shortcut to a handler in which every hook is
+// optional. Unlike NewInlineHandler, the hooks receive the attributes
+// accumulated with WithAttrs and WithGroup as a resolved, grouped list, ready
+// to use, instead of raw groups and attributes.
+//
+// Example usage:
+//
+//	handler := slogmulti.NewInlineHandlerWithOptions(
+//	    slogmulti.WithMinLevel(slog.LevelWarn),
+//	    slogmulti.OnHandle(func(ctx context.Context, record slog.Record, attrs []slog.Attr) error {
+//	        // attrs: [env=prod http=[status=500]]
+//	        return alerting.Send(record.Message, attrs)
+//	    }),
+//	)
+//
+//	slog.New(handler).With("env", "prod").WithGroup("http").Error("boom", "status", 500)
+//
+// Args:
+//
+//	opts: WithMinLevel, OnEnabled and OnHandle
+//
+// Returns:
+//
+//	A slog.Handler. Without OnEnabled, every level above the minimum level is
+//	enabled. Without OnHandle, records are discarded.
+//
+// It panics when a middleware option (OnEnabledNext, OnHandleNext...) is passed.
+func NewInlineHandlerWithOptions(opts ...InlineOption) slog.Handler {
+	options := newInlineOptions(opts)
+	if len(options.middlewareOptions) > 0 {
+		panic(fmt.Sprintf("slog-multi: %s is not a handler option", options.middlewareOptions[0]))
+	}
+
+	return &InlineOptionsHandler{
+		options: options,
+		groups:  []string{},
+		attrs:   []slog.Attr{},
+	}
+}
+
+// Ensure InlineOptionsHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*InlineOptionsHandler)(nil)
+
+// InlineOptionsHandler is a handler built with NewInlineHandlerWithOptions.
+type InlineOptionsHandler struct {
+	options *inlineOptions
+	groups  []string
+	// attrs are resolved and nested under their groups
+	attrs []slog.Attr
+}
+
+// Implements slog.Handler
+func (h *InlineOptionsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if !h.options.enabledLevel(level) {
+		return false
+	}
+
+	if h.options.enabled == nil {
+		return true
+	}
+
+	return h.options.enabled(ctx, level, h.attrs)
+}
+
+// Implements slog.Handler
+func (h *InlineOptionsHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.options.handle == nil || !h.options.enabledLevel(record.Level) {
+		return nil
+	}
+
+	attrs := h.attrs
+	if record.NumAttrs() > 0 {
+		attrs = slogattr.Resolve(mergeRecordAttrs(h.attrs, h.groups, &record))
+	}
+
+	return h.options.handle(ctx, record, attrs)
+}
+
+// Implements slog.Handler
+func (h *InlineOptionsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs = slogattr.Resolve(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+
+	return &InlineOptionsHandler{
+		options: h.options,
+		groups:  h.groups,
+		attrs:   slogcommon.AppendAttrsToGroup(h.groups, h.attrs, attrs...),
+	}
+}
+
+// Implements slog.Handler
+func (h *InlineOptionsHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	newGroups := make([]string, 0, len(h.groups)+1)
+	newGroups = append(newGroups, h.groups...)
+	newGroups = append(newGroups, name)
+	return &InlineOptionsHandler{
+		options: h.options,
+		groups:  newGroups,
+		attrs:   h.attrs,
+	}
+}
+
+// Implements slogmulti.Inspector
+func (h *InlineOptionsHandler) Inspect() HandlerNode {
+	return inspectNode("InlineOptionsHandler", h.groups, h.attrs)
+}
@@ -0,0 +1,160 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type inlineOptionsUser struct {
+	id string
+}
+
+func (u inlineOptionsUser) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", u.id))
+}
+
+func TestInlineHandlerWithOptions(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var got []slog.Attr
+	var gotRecord slog.Record
+	handler := NewInlineHandlerWithOptions(
+		OnHandle(func(ctx context.Context, record slog.Record, attrs []slog.Attr) error {
+			gotRecord = record
+			got = attrs
+			return nil
+		}),
+	)
+
+	logger := slog.New(handler).
+		With("env", "prod", slog.Group("", slog.String("inline", "yes"))).
+		WithGroup("http").
+		With("method", "GET").
+		WithGroup("empty")
+	logger.Info("hello", "user", inlineOptionsUser{id: "42"}, slog.Group("nothing"))
+
+	is.Equal("hello", gotRecord.Message)
+	is.Equal(
+		map[string]any{
+			"env":    "prod",
+			"inline": "yes",
+			"http": map[string]any{
+				"method": "GET",
+				"empty": map[string]any{
+					"user": map[string]any{"id": "42"},
+				},
+			},
+		},
+		flattenForTest(got),
+	)
+
+	// without record attributes, groups left empty are not reported
+	logger.Info("no attrs")
+	is.Equal(map[string]any{"env": "prod", "inline": "yes", "http": map[string]any{"method": "GET"}}, flattenForTest(got))
+}
+
+func TestInlineHandlerWithOptionsResolved(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var resolved *ResolvedRecord
+	handler := NewInlineHandlerWithOptions(
+		OnHandleResolved(func(ctx context.Context, record *ResolvedRecord) error {
+			resolved = record
+			return nil
+		}),
+	)
+
+	slog.New(handler).With("env", "prod").WithGroup("g").With("b", 2).Warn("hello", "a", 1)
+	is.Equal("hello", resolved.Message())
+	is.Equal(slog.LevelWarn, resolved.Level())
+	is.Equal(map[string]any{"env": "prod", "g": map[string]any{"b": int64(2), "a": int64(1)}}, resolved.Map())
+
+	value, ok := resolved.Lookup("g.b")
+	is.True(ok)
+	is.EqualValues(2, value.Int64())
+
+	is.PanicsWithValue("slog-multi: OnHandleResolved is not a middleware option", func() {
+		NewInlineMiddlewareWithOptions(OnHandleResolved(func(ctx context.Context, record *ResolvedRecord) error { return nil }))
+	})
+}
+
+func TestInlineHandlerWithOptionsDefaults(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	handler := NewInlineHandlerWithOptions()
+	is.True(handler.Enabled(context.Background(), slog.LevelDebug))
+	is.NoError(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)))
+	is.Same(handler, handler.WithGroup(""))
+	is.Same(handler, handler.WithAttrs(nil))
+
+	is.PanicsWithValue("slog-multi: OnHandleNext is not a handler option", func() {
+		NewInlineHandlerWithOptions(OnHandleNext(func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+			return nil
+		}))
+	})
+}
+
+func TestInlineHandlerWithOptionsMinLevel(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var level slog.LevelVar
+	level.Set(slog.LevelWarn)
+
+	var count int
+	var enabledAttrs []slog.Attr
+	err := errors.New("boom")
+	handler := NewInlineHandlerWithOptions(
+		WithMinLevel(&level),
+		OnEnabled(func(ctx context.Context, level slog.Level, attrs []slog.Attr) bool {
+			enabledAttrs = attrs
+			return level != slog.LevelError
+		}),
+		OnHandle(func(ctx context.Context, record slog.Record, attrs []slog.Attr) error {
+			count++
+			return err
+		}),
+	).WithAttrs([]slog.Attr{slog.String("env", "prod")})
+
+	is.False(handler.Enabled(context.Background(), slog.LevelInfo))
+	is.Nil(enabledAttrs)
+	is.True(handler.Enabled(context.Background(), slog.LevelWarn))
+	is.Equal([]slog.Attr{slog.String("env", "prod")}, enabledAttrs)
+	is.False(handler.Enabled(context.Background(), slog.LevelError))
+
+	// records below the minimum level are dropped, even when Enabled is bypassed
+	is.NoError(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)))
+	is.ErrorIs(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelWarn, "hello", 0)), err)
+	is.Equal(1, count)
+
+	level.Set(slog.LevelDebug)
+	is.True(handler.Enabled(context.Background(), slog.LevelInfo))
+}
+
+func TestInlineHandlerWithOptionsInspect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	handler := NewInlineHandlerWithOptions().WithGroup("g").WithAttrs([]slog.Attr{slog.String("foo", "bar")})
+	is.Equal("InlineOptionsHandler groups=g attrs={g.foo=bar}\n", Describe(handler))
+}
+
+func flattenForTest(attrs []slog.Attr) map[string]any {
+	output := map[string]any{}
+	for _, attr := range attrs {
+		if attr.Value.Kind() == slog.KindGroup {
+			output[attr.Key] = flattenForTest(attr.Value.Group())
+			continue
+		}
+		output[attr.Key] = attr.Value.Any()
+	}
+	return output
+}
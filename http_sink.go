@@ -0,0 +1,298 @@
+package slogmulti
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// HTTPFormat is the encoding of the batches sent by an HTTP sink.
+type HTTPFormat string
+
+const (
+	// HTTPFormatJSON sends a batch as a JSON array of objects.
+	HTTPFormatJSON HTTPFormat = "json"
+	// HTTPFormatNDJSON sends a batch as newline-delimited JSON objects.
+	HTTPFormatNDJSON HTTPFormat = "ndjson"
+)
+
+// HTTPSinkOption configures an HTTP sink.
+type HTTPSinkOption struct {
+	// URL is the endpoint receiving the batches. Required.
+	URL string
+	// Method is the HTTP method. Default: POST.
+	Method string
+	// Format is the encoding of the batches. Default: HTTPFormatJSON.
+	Format HTTPFormat
+	// Headers are added to every request (eg: Authorization).
+	Headers map[string]string
+	// Gzip compresses the request body.
+	Gzip bool
+	// Client sends the requests. Default: http.DefaultClient.
+	Client *http.Client
+
+	// Converter builds the JSON object of an entry. A batch that cannot be
+	// encoded is dropped without retry.
+	// Default: "time", "level" and "msg" keys, followed by the attributes.
+	Converter func(entry Entry) any
+
+	// Batch configures the thresholds and the retry policy. When
+	// Batch.ShouldRetry is nil, errors are classified with IsRetryableHTTPError.
+	Batch BatchOption
+}
+
+// HTTPError is returned when the endpoint of an HTTP sink responds with a
+// non-2xx status.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	// Body is the beginning of the response body, for troubleshooting.
+	Body string
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("slog-multi: http sink: unexpected status %s", e.Status)
+	}
+	return fmt.Sprintf("slog-multi: http sink: unexpected status %s: %s", e.Status, e.Body)
+}
+
+// Retryable reports whether the request may succeed later: request timeouts (408),
+// too early (425), rate limiting (429) and server errors (5xx) are retryable,
+// other client errors are not.
+func (e *HTTPError) Retryable() bool {
+	switch {
+	case e.StatusCode == http.StatusRequestTimeout,
+		e.StatusCode == http.StatusTooEarly,
+		e.StatusCode == http.StatusTooManyRequests,
+		e.StatusCode >= 500:
+		return true
+	}
+
+	return false
+}
+
+// IsRetryableHTTPError classifies the errors of an HTTP sink: HTTPError
+// according to its status, encoding errors and canceled requests are not
+// retryable, and other (network) errors are.
+func IsRetryableHTTPError(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Retryable()
+	}
+
+	var encodeErr *httpEncodeError
+	if errors.As(err, &encodeErr) {
+		return false
+	}
+
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// HTTPSink creates a handler sending batches of records as JSON or NDJSON to an
+// HTTP endpoint. It is built on Batch: see BatchOption for thresholds and retries.
+//
+// Errors wrap *HTTPError when the endpoint responds with a non-2xx status, so
+// that they can be inspected with errors.As in a RecoverHandlerError callback.
+// To fail over to another handler, with Failover or Pool, record by record,
+// set Batch.MaxSize to 1: errors are only returned by the record filling a batch.
+//
+// Example usage:
+//
+//	sink := slogmulti.HTTPSink(slogmulti.HTTPSinkOption{
+//	    URL:     "https://logs.example.com/ingest",
+//	    Format:  slogmulti.HTTPFormatNDJSON,
+//	    Headers: map[string]string{"Authorization": "Bearer " + token},
+//	    Gzip:    true,
+//	    Batch:   slogmulti.BatchOption{MaxSize: 500, Interval: 5 * time.Second, MaxRetries: 3},
+//	})
+//	defer slogmulti.Shutdown(context.Background(), sink)
+//
+// Args:
+//
+//	opts: The endpoint, encoding and batching configuration
+//
+// Returns:
+//
+//	A batch handler sending records to the endpoint
+func HTTPSink(opts HTTPSinkOption) *BatchHandler {
+	if opts.URL == "" {
+		panic("slog-multi: url is required")
+	}
+	if opts.Method == "" {
+		opts.Method = http.MethodPost
+	}
+	if opts.Format == "" {
+		opts.Format = HTTPFormatJSON
+	}
+	if opts.Format != HTTPFormatJSON && opts.Format != HTTPFormatNDJSON {
+		panic(fmt.Sprintf("slog-multi: unknown http format %q", opts.Format))
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Converter == nil {
+		opts.Converter = defaultHTTPConverter
+	}
+	if opts.Batch.ShouldRetry == nil {
+		opts.Batch.ShouldRetry = IsRetryableHTTPError
+	}
+
+	sink := &httpSink{opts: opts}
+	return Batch(sink.send, opts.Batch)
+}
+
+type httpSink struct {
+	opts HTTPSinkOption
+}
+
+// httpEncodeError is returned when a batch cannot be encoded, eg: by a custom
+// Converter. Encoding the same batch again would fail the same way.
+type httpEncodeError struct {
+	err error
+}
+
+func (e *httpEncodeError) Error() string {
+	return fmt.Sprintf("slog-multi: http sink: %v", e.err)
+}
+
+func (e *httpEncodeError) Unwrap() error {
+	return e.err
+}
+
+func (s *httpSink) send(ctx context.Context, entries []Entry) error {
+	body, err := s.encode(entries)
+	if err != nil {
+		return &httpEncodeError{err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, s.opts.Method, s.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if s.opts.Format == HTTPFormatNDJSON {
+		req.Header.Set("Content-Type", "application/x-ndjson")
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.opts.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for key, value := range s.opts.Headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// read the beginning of the body for errors, and drain it to reuse the connection
+	excerpt, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return &HTTPError{
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			Body:       string(bytes.TrimSpace(excerpt)),
+		}
+	}
+
+	return nil
+}
+
+func (s *httpSink) encode(entries []Entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if s.opts.Gzip {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+
+	if s.opts.Format == HTTPFormatNDJSON {
+		encoder := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := encoder.Encode(s.opts.Converter(entry)); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		payload := make([]any, 0, len(entries))
+		for _, entry := range entries {
+			payload = append(payload, s.opts.Converter(entry))
+		}
+		if err := json.NewEncoder(w).Encode(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// defaultHTTPConverter encodes an entry like slog.JSONHandler does. Values are
+// encoded one by one: a value that cannot be encoded (NaN, channel, function...)
+// is replaced by an "!ERROR:" string, instead of failing the whole batch.
+func defaultHTTPConverter(entry Entry) any {
+	output := make(map[string]any, len(entry.Attrs)+3)
+	if !entry.Time.IsZero() {
+		output[slog.TimeKey] = entry.Time
+	}
+	output[slog.LevelKey] = entry.Level.String()
+	output[slog.MessageKey] = entry.Message
+
+	for key, value := range jsonAttrs(entry.Attrs) {
+		output[key] = value
+	}
+
+	return output
+}
+
+func jsonAttrs(attrs []slog.Attr) map[string]any {
+	output := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		output[attr.Key] = jsonValue(attr.Value)
+	}
+	return output
+}
+
+func jsonValue(value slog.Value) any {
+	switch value.Kind() {
+	case slog.KindGroup:
+		return jsonAttrs(value.Group())
+	case slog.KindDuration:
+		return value.Duration().Nanoseconds()
+	case slog.KindString, slog.KindInt64, slog.KindUint64, slog.KindBool:
+		return value.Any()
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			if _, ok := err.(json.Marshaler); !ok {
+				return err.Error()
+			}
+		}
+	}
+
+	// floats, times and arbitrary values may fail: encode them now
+	data, err := json.Marshal(value.Any())
+	if err != nil {
+		return "!ERROR:" + err.Error()
+	}
+	return json.RawMessage(data)
+}
@@ -0,0 +1,241 @@
+package slogmulti
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samber/slog-multi/slogmultitest"
+	"github.com/stretchr/testify/assert"
+)
+
+type httpRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newHTTPCollector starts a server recording requests and answering with the
+// given statuses, then 204.
+func newHTTPCollector(statuses ...int) (*httptest.Server, func() []httpRequest) {
+	var mu sync.Mutex
+	var calls atomic.Int64
+	requests := []httpRequest{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1)) - 1
+		if call < len(statuses) {
+			w.WriteHeader(statuses[call])
+			_, _ = w.Write([]byte("  try again later\n"))
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, httpRequest{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	return server, func() []httpRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]httpRequest{}, requests...)
+	}
+}
+
+func TestHTTPSinkJSON(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	server, requests := newHTTPCollector()
+	defer server.Close()
+
+	sink := HTTPSink(HTTPSinkOption{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Batch:   BatchOption{MaxSize: 2},
+	})
+
+	logger := slog.New(sink).With("env", "prod").WithGroup("http")
+	logger.Info("a", "status", 200, "latency", time.Millisecond)
+	logger.Error("b", "error", errors.New("boom"))
+
+	is.Len(requests(), 1)
+	request := requests()[0]
+	is.Equal("application/json", request.header.Get("Content-Type"))
+	is.Equal("Bearer token", request.header.Get("Authorization"))
+
+	payload := []map[string]any{}
+	is.NoError(json.Unmarshal(request.body, &payload))
+	is.Len(payload, 2)
+	is.NotEmpty(payload[0]["time"])
+	delete(payload[0], "time")
+	delete(payload[1], "time")
+	is.Equal([]map[string]any{
+		{"level": "INFO", "msg": "a", "env": "prod", "http": map[string]any{"status": float64(200), "latency": float64(time.Millisecond)}},
+		{"level": "ERROR", "msg": "b", "env": "prod", "http": map[string]any{"error": "boom"}},
+	}, payload)
+
+	is.NoError(sink.Close(context.Background()))
+}
+
+func TestHTTPSinkNDJSONGzip(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	server, requests := newHTTPCollector()
+	defer server.Close()
+
+	sink := HTTPSink(HTTPSinkOption{
+		URL:    server.URL,
+		Method: http.MethodPut,
+		Format: HTTPFormatNDJSON,
+		Gzip:   true,
+		Converter: func(entry Entry) any {
+			return map[string]any{"message": entry.Message}
+		},
+	})
+
+	logger := slog.New(sink)
+	logger.Info("a")
+	logger.Info("b")
+	is.Empty(requests())
+	is.NoError(Shutdown(context.Background(), sink))
+
+	is.Len(requests(), 1)
+	request := requests()[0]
+	is.Equal("application/x-ndjson", request.header.Get("Content-Type"))
+	is.Equal("gzip", request.header.Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(bytes.NewReader(request.body))
+	is.NoError(err)
+	lines := []string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	is.Equal([]string{`{"message":"a"}`, `{"message":"b"}`}, lines)
+}
+
+func TestHTTPSinkErrorClassification(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// server errors are retried
+	server, requests := newHTTPCollector(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	defer server.Close()
+
+	sink := HTTPSink(HTTPSinkOption{URL: server.URL, Batch: BatchOption{MaxSize: 1, MaxRetries: 2, RetryBackoff: time.Millisecond}})
+	is.NoError(sink.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)))
+	is.Len(requests(), 1)
+
+	// client errors are not
+	server, requests = newHTTPCollector(http.StatusBadRequest)
+	defer server.Close()
+
+	sink = HTTPSink(HTTPSinkOption{URL: server.URL, Batch: BatchOption{MaxSize: 1, MaxRetries: 2, RetryBackoff: time.Millisecond}})
+	err := sink.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+
+	var httpErr *HTTPError
+	is.ErrorAs(err, &httpErr)
+	is.Equal(http.StatusBadRequest, httpErr.StatusCode)
+	is.Equal("try again later", httpErr.Body)
+	is.False(httpErr.Retryable())
+	is.Contains(err.Error(), "after 1 attempt(s): slog-multi: http sink: unexpected status 400 Bad Request: try again later")
+	is.Empty(requests())
+
+	is.True((&HTTPError{StatusCode: http.StatusBadGateway}).Retryable())
+	is.True(IsRetryableHTTPError(errors.New("connection refused")))
+	is.False(IsRetryableHTTPError(context.Canceled))
+
+	is.Panics(func() { HTTPSink(HTTPSinkOption{}) })
+	is.Panics(func() { HTTPSink(HTTPSinkOption{URL: server.URL, Format: "xml"}) })
+}
+
+func TestHTTPSinkUnencodableValues(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	server, requests := newHTTPCollector()
+	defer server.Close()
+
+	sink := HTTPSink(HTTPSinkOption{URL: server.URL, Batch: BatchOption{MaxSize: 2}})
+	logger := slog.New(sink)
+	logger.Info("first", "ratio", 0.5)
+	logger.Info("second", "nan", math.NaN(), "ch", make(chan int), "fn", func() {}, slog.Group("g", "inf", math.Inf(1)))
+
+	is.Len(requests(), 1)
+	batch := []map[string]any{}
+	is.NoError(json.Unmarshal(requests()[0].body, &batch))
+	is.Len(batch, 2)
+	is.Equal(0.5, batch[0]["ratio"])
+	is.Equal("!ERROR:json: unsupported value: NaN", batch[1]["nan"])
+	is.Equal("!ERROR:json: unsupported type: chan int", batch[1]["ch"])
+	is.Equal("!ERROR:json: unsupported type: func()", batch[1]["fn"])
+	is.Equal(map[string]any{"inf": "!ERROR:json: unsupported value: +Inf"}, batch[1]["g"])
+
+	// encoding errors of a custom converter are not retried
+	calls := 0
+	sink = HTTPSink(HTTPSinkOption{
+		URL: server.URL,
+		Converter: func(entry Entry) any {
+			calls++
+			return map[string]any{"ch": make(chan int)}
+		},
+		Batch: BatchOption{MaxSize: 1, MaxRetries: 3, RetryBackoff: time.Millisecond},
+	})
+	err := sink.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+	is.ErrorContains(err, "after 1 attempt(s): slog-multi: http sink: json: unsupported type: chan int")
+	is.False(IsRetryableHTTPError(err))
+	is.Equal(1, calls)
+	is.Len(requests(), 1)
+}
+
+func TestHTTPSinkFailoverAndRecovery(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	down, _ := newHTTPCollector(http.StatusInternalServerError, http.StatusInternalServerError)
+	defer down.Close()
+	up, requests := newHTTPCollector()
+	defer up.Close()
+
+	backup := slogmultitest.NewRecordingHandler(nil)
+	primary := HTTPSink(HTTPSinkOption{URL: down.URL, Batch: BatchOption{MaxSize: 1}})
+	secondary := HTTPSink(HTTPSinkOption{URL: up.URL, Batch: BatchOption{MaxSize: 1}})
+
+	// failover: the record goes to the backup when the endpoint fails
+	logger := slog.New(Failover()(primary, backup))
+	logger.Info("first")
+	slogmultitest.AssertLogged(t, backup, "first")
+
+	// pool: the record is retried on another member
+	logger = slog.New(Pool()(primary, secondary))
+	logger.Info("second")
+	is.Eventually(func() bool { return len(requests()) == 1 }, time.Second, time.Millisecond)
+
+	// recovery: the callback receives the HTTPError
+	var status int
+	recovery := RecoverHandlerError(func(ctx context.Context, record slog.Record, err error) {
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			status = httpErr.StatusCode
+		}
+	})
+	failing, _ := newHTTPCollector(http.StatusUnauthorized)
+	defer failing.Close()
+	slog.New(recovery(HTTPSink(HTTPSinkOption{URL: failing.URL, Batch: BatchOption{MaxSize: 1}}))).Info("third")
+	is.Equal(http.StatusUnauthorized, status)
+}
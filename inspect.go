@@ -0,0 +1,235 @@
+package slogmulti
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	slogcommon "github.com/samber/slog-common"
+)
+
+// Inspector is implemented by handlers exposing their structure to Inspect.
+// Every handler and middleware of this package implements Inspector.
+type Inspector interface {
+	Inspect() HandlerNode
+}
+
+// Ensure built-in handlers implement Inspector at compile time
+var (
+	_ Inspector = (*FanoutHandler)(nil)
+	_ Inspector = (*FailoverHandler)(nil)
+	_ Inspector = (*PoolHandler)(nil)
+	_ Inspector = (*FirstMatchHandler)(nil)
+	_ Inspector = (*RouterHandler)(nil)
+	_ Inspector = (*RoutableHandler)(nil)
+	_ Inspector = (*HandlerErrorRecovery)(nil)
+	_ Inspector = (*InlineHandler)(nil)
+	_ Inspector = (*HandleInlineHandler)(nil)
+	_ Inspector = (*InlineOptionsHandler)(nil)
+	_ Inspector = (*InlineMiddleware)(nil)
+	_ Inspector = (*EnabledInlineMiddleware)(nil)
+	_ Inspector = (*HandleInlineMiddleware)(nil)
+	_ Inspector = (*WithAttrsInlineMiddleware)(nil)
+	_ Inspector = (*WithGroupInlineMiddleware)(nil)
+	_ Inspector = (*TransformHandler)(nil)
+	_ Inspector = (*ContextAttrsHandler)(nil)
+	_ Inspector = (*ErrorFormatterHandler)(nil)
+	_ Inspector = (*TraceAttrsHandler)(nil)
+	_ Inspector = (*EnrichHandler)(nil)
+	_ Inspector = (*LevelGateHandler)(nil)
+	_ Inspector = (*LevelOverrideHandler)(nil)
+	_ Inspector = (*ConditionalHandler)(nil)
+	_ Inspector = (*TeeHandler)(nil)
+	_ Inspector = (*BatchHandler)(nil)
+	_ Inspector = (*AggregateHandler)(nil)
+	_ Inspector = (*MetricsHandler)(nil)
+)
+
+// HandlerNode describes a handler of a handler tree.
+type HandlerNode struct {
+	// Type is the kind of handler (eg: "Fanout", "Route", "*slog.JSONHandler")
+	Type string `json:"type"`
+	// Name is an optional name (eg: the branch name of a LevelGate)
+	Name string `json:"name,omitempty"`
+	// Route is a label describing the predicates of a route
+	Route string `json:"route,omitempty"`
+	// Groups is the group hierarchy accumulated by the handler
+	Groups []string `json:"groups,omitempty"`
+	// Attrs are the attributes accumulated by the handler, nested under their groups
+	Attrs map[string]any `json:"attrs,omitempty"`
+	// Children are the handlers receiving records from this handler
+	Children []HandlerNode `json:"children,omitempty"`
+}
+
+// Inspect walks a handler tree and returns its description. Handlers implementing
+// Inspector describe themselves and their children. Other handlers are reported
+// as leaves, using their Go type.
+//
+// Example usage:
+//
+//	handler := slogmulti.Fanout(
+//	    slogmulti.Router().Add(stdoutHandler, slogmulti.LevelIs(slog.LevelInfo)).Handler(),
+//	    slogmulti.Pipe(slogmulti.RecoverHandlerError(recovery)).Handler(datadogHandler),
+//	)
+//
+//	fmt.Println(slogmulti.Inspect(handler))
+//	// Fanout
+//	// ├── Router
+//	// │   └── Route route="LevelIs()"
+//	// │       └── *slog.TextHandler
+//	// └── Recover
+//	//     └── *slogdatadog.DatadogHandler
+//
+//	output, _ := json.Marshal(slogmulti.Inspect(handler))
+//
+// Args:
+//
+//	handler: The root of the handler tree
+//
+// Returns:
+//
+//	The description of the handler tree
+func Inspect(handler slog.Handler) HandlerNode {
+	if handler == nil {
+		return HandlerNode{Type: "<nil>"}
+	}
+
+	if inspector, ok := handler.(Inspector); ok {
+		return inspector.Inspect()
+	}
+
+	return HandlerNode{Type: fmt.Sprintf("%T", handler)}
+}
+
+// Describe is a shortcut for Inspect(handler).String().
+func Describe(handler slog.Handler) string {
+	return Inspect(handler).String()
+}
+
+// String renders the handler tree as indented text.
+func (n HandlerNode) String() string {
+	var sb strings.Builder
+	n.write(&sb, "", "")
+	return sb.String()
+}
+
+func (n HandlerNode) write(sb *strings.Builder, prefix string, childPrefix string) {
+	sb.WriteString(prefix)
+	sb.WriteString(n.Type)
+	if n.Name != "" {
+		fmt.Fprintf(sb, " name=%q", n.Name)
+	}
+	if n.Route != "" {
+		fmt.Fprintf(sb, " route=%q", n.Route)
+	}
+	if len(n.Groups) > 0 {
+		fmt.Fprintf(sb, " groups=%s", strings.Join(n.Groups, "."))
+	}
+	if len(n.Attrs) > 0 {
+		fmt.Fprintf(sb, " attrs={%s}", strings.Join(flattenAttrsMap("", n.Attrs), " "))
+	}
+	sb.WriteString("\n")
+
+	for i, child := range n.Children {
+		if i == len(n.Children)-1 {
+			child.write(sb, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			child.write(sb, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// flattenAttrsMap returns sorted "dotted.key=value" pairs.
+func flattenAttrsMap(prefix string, attrs map[string]any) []string {
+	out := []string{}
+	for key, value := range attrs {
+		if nested, ok := value.(map[string]any); ok {
+			out = append(out, flattenAttrsMap(prefix+key+".", nested)...)
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s%s=%v", prefix, key, value))
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// inspectNode builds a node with accumulated groups and attributes.
+func inspectNode(typ string, groups []string, attrs []slog.Attr, children ...slog.Handler) HandlerNode {
+	node := HandlerNode{
+		Type:     typ,
+		Children: inspectHandlers(children...),
+	}
+
+	if len(groups) > 0 {
+		node.Groups = groups
+	}
+	if len(attrs) > 0 {
+		node.Attrs = slogcommon.AttrsToMap(attrs...)
+	}
+
+	return node
+}
+
+func inspectHandlers[T slog.Handler](handlers ...T) []HandlerNode {
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	nodes := make([]HandlerNode, 0, len(handlers))
+	for _, handler := range handlers {
+		nodes = append(nodes, Inspect(handler))
+	}
+
+	return nodes
+}
+
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$`)
+
+// routeLabel returns a label for the predicates and matchers of a route, derived
+// from function names (eg: "LevelIs() && AttrValueIs()").
+func routeLabel(predicates []func(ctx context.Context, r slog.Record) bool, matchers []Matcher) string {
+	if len(predicates) == 0 && len(matchers) == 0 {
+		return "*"
+	}
+
+	labels := make([]string, 0, len(predicates)+len(matchers))
+	for _, predicate := range predicates {
+		labels = append(labels, funcLabel(predicate))
+	}
+	for _, matcher := range matchers {
+		labels = append(labels, matcherLabel(matcher))
+	}
+
+	return strings.Join(labels, " && ")
+}
+
+func funcLabel(fn any) string {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func {
+		return fmt.Sprintf("%T", fn)
+	}
+	if value.IsNil() {
+		return "<nil>"
+	}
+
+	f := runtime.FuncForPC(value.Pointer())
+	if f == nil {
+		return "func()"
+	}
+
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return closureSuffix.ReplaceAllString(name, "") + "()"
+}
@@ -0,0 +1,68 @@
+package slogmulti
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewLevelRegistry()
+	sink := slog.NewTextHandler(bytes.NewBufferString(""), nil)
+
+	handler := Fanout(
+		Fanout(
+			Router().
+				Add(sink, LevelIs(slog.LevelError), AttrValueIs("scope", "auth")).
+				Add(sink).
+				FirstMatch().
+				Handler(),
+			Failover()(sink, Pipe(RecoverHandlerError(func(context.Context, slog.Record, error) {})).Handler(sink)),
+		),
+		Pipe(LevelGate(registry, "stdout"), Transform()).Handler(Pool()(sink, &noopHandler{})),
+	).WithAttrs([]slog.Attr{slog.String("env", "prod")}).WithGroup("http").WithAttrs([]slog.Attr{slog.Int("status", 200)})
+
+	expected := `Fanout
+├── FirstMatch groups=http attrs={env=prod http.status=200}
+│   ├── Route route="LevelIs() && AttrValueIs()"
+│   │   └── *slog.TextHandler
+│   └── Route route="*"
+│       └── *slog.TextHandler
+├── Failover
+│   ├── *slog.TextHandler
+│   └── Recover
+│       └── *slog.TextHandler
+└── LevelGate name="stdout"
+    └── Transform groups=http attrs={env=prod http.status=200}
+        └── Pool
+            ├── *slog.TextHandler
+            └── *slogmulti.noopHandler
+`
+	is.Equal(expected, Describe(handler))
+	is.Equal(expected, Inspect(handler).String())
+
+	output, err := json.Marshal(Inspect(Router().Add(sink, MessageIs("hello")).Handler().WithGroup("g")))
+	is.NoError(err)
+	is.JSONEq(`{"type":"Router","groups":["g"],"children":[{"type":"Route","route":"MessageIs()","children":[{"type":"*slog.TextHandler"}]}]}`, string(output))
+}
+
+func TestInspectLeaf(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.Equal(HandlerNode{Type: "<nil>"}, Inspect(nil))
+	is.Equal(HandlerNode{Type: "*slogmulti.noopHandler"}, Inspect(&noopHandler{}))
+
+	inline := NewInlineHandler(
+		func(ctx context.Context, groups []string, attrs []slog.Attr, level slog.Level) bool { return true },
+		func(ctx context.Context, groups []string, attrs []slog.Attr, record slog.Record) error { return nil },
+	).WithAttrs([]slog.Attr{slog.String("foo", "bar")}).WithGroup("g")
+	is.Equal(HandlerNode{Type: "InlineHandler", Groups: []string{"g"}, Attrs: map[string]any{"foo": "bar"}}, Inspect(inline))
+}
@@ -0,0 +1,81 @@
+// Package slogattr holds the attribute helpers shared by slogmulti and slogmultitest.
+package slogattr
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// SplitPath splits a dotted attribute path ("error.message") into its segments.
+func SplitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// FindByPath returns the last attribute located at the given path.
+// Intermediate segments are group names. Groups sharing the same key are all
+// inspected, in order, so that the last matching attribute wins, like slog handlers do.
+func FindByPath(attrs []slog.Attr, path []string) (slog.Attr, bool) {
+	if len(path) == 0 {
+		return slog.Attr{}, false
+	}
+
+	var found slog.Attr
+	var ok bool
+
+	for _, attr := range attrs {
+		if attr.Key != path[0] {
+			continue
+		}
+
+		if len(path) == 1 {
+			found, ok = attr, true
+			continue
+		}
+
+		value := attr.Value.Resolve()
+		if value.Kind() != slog.KindGroup {
+			continue
+		}
+
+		if child, childOk := FindByPath(value.Group(), path[1:]); childOk {
+			found, ok = child, true
+		}
+	}
+
+	return found, ok
+}
+
+// Resolve resolves slog.LogValuer values, recursively, and applies the
+// slog.Handler rules: empty attributes and empty groups are ignored, and groups
+// with an empty key are inlined. The input slice is never mutated.
+func Resolve(attrs []slog.Attr) []slog.Attr {
+	output := make([]slog.Attr, 0, len(attrs))
+
+	for _, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+
+		// zero attribute (Value.Equal may panic on non-comparable values)
+		if attr.Key == "" && attr.Value.Kind() == slog.KindAny && attr.Value.Any() == nil {
+			continue
+		}
+
+		if attr.Value.Kind() == slog.KindGroup {
+			children := Resolve(attr.Value.Group())
+			if len(children) == 0 {
+				continue
+			}
+			if attr.Key == "" {
+				output = append(output, children...)
+				continue
+			}
+			attr.Value = slog.GroupValue(children...)
+		}
+
+		output = append(output, attr)
+	}
+
+	return output
+}
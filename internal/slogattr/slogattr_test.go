@@ -0,0 +1,62 @@
+package slogattr
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	id string
+}
+
+func (u user) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", u.id))
+}
+
+func TestFindByPath(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	attrs := []slog.Attr{
+		slog.Group("http", slog.Int("status", 200)),
+		slog.Any("user", user{id: "u-1"}),
+		slog.String("env", "dev"),
+		slog.Group("http", slog.Int("status", 500)),
+	}
+
+	// the last attribute wins
+	attr, ok := FindByPath(attrs, SplitPath("http.status"))
+	is.True(ok)
+	is.EqualValues(500, attr.Value.Int64())
+
+	// slog.LogValuer groups are resolved
+	attr, ok = FindByPath(attrs, SplitPath("user.id"))
+	is.True(ok)
+	is.Equal("u-1", attr.Value.String())
+
+	_, ok = FindByPath(attrs, SplitPath("env.name"))
+	is.False(ok)
+	_, ok = FindByPath(attrs, SplitPath(""))
+	is.False(ok)
+	is.Nil(SplitPath(""))
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	attrs := []slog.Attr{
+		{},
+		slog.Any("user", user{id: "u-1"}),
+		slog.Group("empty"),
+		slog.Group("", slog.String("inlined", "yes")),
+	}
+
+	is.Equal([]slog.Attr{
+		slog.Group("user", slog.String("id", "u-1")),
+		slog.String("inlined", "yes"),
+	}, Resolve(attrs))
+	is.Len(attrs, 4)
+}
@@ -0,0 +1,421 @@
+package slogmulti
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUnknownLevel is returned when updating a level that has not been registered.
+var ErrUnknownLevel = errors.New("slog-multi: unknown level name")
+
+// LevelRegistry holds named slog.LevelVar, one per logging branch (eg: "stdout", "datadog", "audit").
+// Levels can be changed at runtime, optionally for a limited duration.
+type LevelRegistry struct {
+	mu       sync.Mutex
+	levels   map[string]*slog.LevelVar
+	defaults map[string]slog.Level
+	timers   map[string]*time.Timer
+	expires  map[string]time.Time
+	policy   LevelOverridePolicy
+}
+
+// LevelState describes the current state of a named level.
+type LevelState struct {
+	Name      string     `json:"name"`
+	Level     slog.Level `json:"level"`
+	Default   slog.Level `json:"default"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// NewLevelRegistry creates an empty LevelRegistry.
+func NewLevelRegistry() *LevelRegistry {
+	return &LevelRegistry{
+		levels:   map[string]*slog.LevelVar{},
+		defaults: map[string]slog.Level{},
+		timers:   map[string]*time.Timer{},
+		expires:  map[string]time.Time{},
+	}
+}
+
+// Register declares a named level with its default value and returns the underlying slog.LevelVar.
+// If the name is already registered, its default value is updated and the current level is reset.
+func (r *LevelRegistry) Register(name string, level slog.Level) *slog.LevelVar {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.defaults[name] = level
+	r.stopTimer(name)
+
+	levelVar, ok := r.levels[name]
+	if !ok {
+		levelVar = &slog.LevelVar{}
+		r.levels[name] = levelVar
+	}
+	levelVar.Set(level)
+
+	return levelVar
+}
+
+// LevelVar returns the slog.LevelVar of a named level, registering it with slog.LevelInfo
+// as default value when missing.
+func (r *LevelRegistry) LevelVar(name string) *slog.LevelVar {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// lookup and registration are atomic, so that a racing caller cannot reset
+	// a level changed with Set in the meantime
+	levelVar, ok := r.levels[name]
+	if !ok {
+		levelVar = &slog.LevelVar{}
+		levelVar.Set(slog.LevelInfo)
+		r.levels[name] = levelVar
+		r.defaults[name] = slog.LevelInfo
+	}
+
+	return levelVar
+}
+
+// Set changes the minimum level of a named branch. When ttl is positive, the
+// default level is restored after ttl (eg: at the end of a debugging session).
+func (r *LevelRegistry) Set(name string, level slog.Level, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	levelVar, ok := r.levels[name]
+	if !ok {
+		return ErrUnknownLevel
+	}
+
+	r.stopTimer(name)
+	levelVar.Set(level)
+
+	if ttl > 0 {
+		var timer *time.Timer
+		timer = time.AfterFunc(ttl, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			// the level might have been changed in the meantime
+			if r.timers[name] != timer {
+				return
+			}
+
+			r.levels[name].Set(r.defaults[name])
+			delete(r.timers, name)
+			delete(r.expires, name)
+		})
+		r.timers[name] = timer
+		r.expires[name] = time.Now().Add(ttl)
+	}
+
+	return nil
+}
+
+// Reset restores the default level of a named branch.
+func (r *LevelRegistry) Reset(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	levelVar, ok := r.levels[name]
+	if !ok {
+		return ErrUnknownLevel
+	}
+
+	r.stopTimer(name)
+	levelVar.Set(r.defaults[name])
+
+	return nil
+}
+
+// SetOverridePolicy sets which branches honour per-context level overrides
+// (see WithLevelOverride). By default, every branch honours them.
+func (r *LevelRegistry) SetOverridePolicy(policy LevelOverridePolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.policy = policy
+}
+
+func (r *LevelRegistry) overridePolicy() LevelOverridePolicy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.policy
+}
+
+// State returns the state of a named level.
+func (r *LevelRegistry) State(name string) (LevelState, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.state(name)
+}
+
+// States returns the state of every registered level, sorted by name.
+func (r *LevelRegistry) States() []LevelState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states := make([]LevelState, 0, len(r.levels))
+	for name := range r.levels {
+		state, _ := r.state(name)
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+
+	return states
+}
+
+func (r *LevelRegistry) state(name string) (LevelState, bool) {
+	levelVar, ok := r.levels[name]
+	if !ok {
+		return LevelState{}, false
+	}
+
+	state := LevelState{
+		Name:    name,
+		Level:   levelVar.Level(),
+		Default: r.defaults[name],
+	}
+	if expiresAt, ok := r.expires[name]; ok {
+		state.ExpiresAt = &expiresAt
+	}
+
+	return state, true
+}
+
+// stopTimer cancels a pending revert. The caller must hold the lock.
+func (r *LevelRegistry) stopTimer(name string) {
+	if timer, ok := r.timers[name]; ok {
+		timer.Stop()
+		delete(r.timers, name)
+		delete(r.expires, name)
+	}
+}
+
+// HTTPHandler returns an http.Handler for reading and changing levels at runtime.
+//
+//	GET    /             -> list every level
+//	GET    /?name=stdout -> read a single level
+//	PUT    /             -> {"name": "stdout", "level": "DEBUG", "ttl": "15m"}
+//	DELETE /?name=stdout -> restore the default level
+//
+// The handler should be mounted behind authentication.
+//
+// Example usage:
+//
+//	http.Handle("/admin/log-levels", registry.HTTPHandler())
+func (r *LevelRegistry) HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			name := req.URL.Query().Get("name")
+			if name == "" {
+				writeJSON(w, http.StatusOK, r.States())
+				return
+			}
+
+			state, ok := r.State(name)
+			if !ok {
+				writeJSONError(w, http.StatusNotFound, ErrUnknownLevel)
+				return
+			}
+			writeJSON(w, http.StatusOK, state)
+
+		case http.MethodPut, http.MethodPost:
+			var body struct {
+				Name  string     `json:"name"`
+				Level slog.Level `json:"level"`
+				TTL   string     `json:"ttl"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				writeJSONError(w, http.StatusBadRequest, err)
+				return
+			}
+
+			var ttl time.Duration
+			if body.TTL != "" {
+				var err error
+				ttl, err = time.ParseDuration(body.TTL)
+				if err != nil || ttl < 0 {
+					writeJSONError(w, http.StatusBadRequest, errors.New("slog-multi: invalid ttl"))
+					return
+				}
+			}
+
+			if err := r.Set(body.Name, body.Level, ttl); err != nil {
+				writeJSONError(w, http.StatusNotFound, err)
+				return
+			}
+
+			state, _ := r.State(body.Name)
+			writeJSON(w, http.StatusOK, state)
+
+		case http.MethodDelete:
+			name := req.URL.Query().Get("name")
+			if err := r.Reset(name); err != nil {
+				writeJSONError(w, http.StatusNotFound, err)
+				return
+			}
+
+			state, _ := r.State(name)
+			writeJSON(w, http.StatusOK, state)
+
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST, DELETE")
+			writeJSONError(w, http.StatusMethodNotAllowed, errors.New("slog-multi: method not allowed"))
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// Ensure LevelGateHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*LevelGateHandler)(nil)
+
+// LevelGateHandler drops records below the current level of a named branch.
+// Records logged with a level override (see WithLevelOverride) bypass the gate,
+// according to the override policy of the registry.
+type LevelGateHandler struct {
+	// next is the gated handler
+	next slog.Handler
+	// name is the branch name in the registry
+	name string
+	// level is the minimum level of the branch
+	level *slog.LevelVar
+	// registry holds the override policy
+	registry *LevelRegistry
+}
+
+// LevelGate creates a middleware that gates the next handler with a named level of the registry.
+// The name is registered with slog.LevelInfo as default value when missing.
+//
+// Example usage:
+//
+//	registry := slogmulti.NewLevelRegistry()
+//	registry.Register("stdout", slog.LevelInfo)
+//	registry.Register("datadog", slog.LevelWarn)
+//
+//	handler := slogmulti.Fanout(
+//	    slogmulti.Pipe(slogmulti.LevelGate(registry, "stdout")).Handler(stdoutHandler),
+//	    slogmulti.Pipe(slogmulti.LevelGate(registry, "datadog")).Handler(datadogHandler),
+//	)
+//
+//	// later, lower the datadog level for 15 minutes
+//	registry.Set("datadog", slog.LevelDebug, 15*time.Minute)
+//
+// Args:
+//
+//	registry: The registry holding the levels
+//	name: The name of the branch
+//
+// Returns:
+//
+//	A middleware gating the next handler
+func LevelGate(registry *LevelRegistry, name string) Middleware {
+	level := registry.LevelVar(name)
+
+	return func(next slog.Handler) slog.Handler {
+		return &LevelGateHandler{
+			next:     next,
+			name:     name,
+			level:    level,
+			registry: registry,
+		}
+	}
+}
+
+// Enabled checks if the level is above the branch level and if the next handler is enabled.
+// When the context overrides the level, the next handler is not checked.
+// This method implements the slog.Handler interface requirement.
+func (h *LevelGateHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	if h.overridden(ctx, l) {
+		return true
+	}
+
+	return l >= h.level.Level() && h.next.Enabled(ctx, l)
+}
+
+// Handle forwards the record to the next handler if its level is above the branch level,
+// or if the context overrides the level.
+// This method implements the slog.Handler interface requirement.
+func (h *LevelGateHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level.Level() && !h.overridden(ctx, r.Level) {
+		return nil
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *LevelGateHandler) overridden(ctx context.Context, l slog.Level) bool {
+	// avoid locking the registry when the context carries no override
+	if _, ok := LevelOverrideFromContext(ctx); !ok {
+		return false
+	}
+
+	return isLevelOverridden(ctx, h.registry.overridePolicy(), h.name, l)
+}
+
+// WithAttrs creates a new LevelGateHandler with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *LevelGateHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LevelGateHandler{
+		next:     h.next.WithAttrs(attrs),
+		name:     h.name,
+		level:    h.level,
+		registry: h.registry,
+	}
+}
+
+// WithGroup creates a new LevelGateHandler with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *LevelGateHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	return &LevelGateHandler{
+		next:     h.next.WithGroup(name),
+		name:     h.name,
+		level:    h.level,
+		registry: h.registry,
+	}
+}
+
+// Flush flushes the next handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *LevelGateHandler) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Close closes the next handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *LevelGateHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Inspect describes the LevelGate middleware, its branch name and the next handler.
+// This method implements the Inspector interface.
+func (h *LevelGateHandler) Inspect() HandlerNode {
+	node := inspectNode("LevelGate", nil, nil, h.next)
+	node.Name = h.name
+	return node
+}
@@ -0,0 +1,171 @@
+package slogmulti
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelGate(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewLevelRegistry()
+	registry.Register("stdout", slog.LevelInfo)
+	registry.Register("audit", slog.LevelWarn)
+
+	stdout := bytes.NewBufferString("")
+	audit := bytes.NewBufferString("")
+
+	logger := slog.New(Fanout(
+		Pipe(LevelGate(registry, "stdout")).Handler(slog.NewTextHandler(stdout, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: remoteTimeReplaceAttr})),
+		Pipe(LevelGate(registry, "audit")).Handler(slog.NewTextHandler(audit, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: remoteTimeReplaceAttr})),
+	))
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	is.Equal("level=INFO msg=info\nlevel=WARN msg=warn\n", stdout.String())
+	is.Equal("level=WARN msg=warn\n", audit.String())
+
+	stdout.Reset()
+	audit.Reset()
+
+	is.NoError(registry.Set("audit", slog.LevelDebug, 0))
+	is.ErrorIs(registry.Set("unknown", slog.LevelDebug, 0), ErrUnknownLevel)
+
+	logger.With("foo", "bar").WithGroup("g").Debug("debug", "a", 1)
+	is.Equal("", stdout.String())
+	is.Equal("level=DEBUG msg=debug foo=bar g.a=1\n", audit.String())
+
+	is.NoError(registry.Reset("audit"))
+	is.False(logger.Enabled(context.Background(), slog.LevelDebug))
+}
+
+func TestLevelRegistryLevelVarRace(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for i := 0; i < 100; i++ {
+		registry := NewLevelRegistry()
+
+		var wg sync.WaitGroup
+		for j := 0; j < 4; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				registry.LevelVar("stdout")
+			}()
+		}
+
+		// a concurrent first call must not reset the level
+		levelVar := registry.LevelVar("stdout")
+		is.NoError(registry.Set("stdout", slog.LevelDebug, 0))
+		wg.Wait()
+
+		is.Same(levelVar, registry.LevelVar("stdout"))
+		is.Equal(slog.LevelDebug, levelVar.Level())
+
+		state, ok := registry.State("stdout")
+		is.True(ok)
+		is.Equal(slog.LevelInfo, state.Default)
+	}
+}
+
+func TestLevelGateTTL(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewLevelRegistry()
+	registry.Register("datadog", slog.LevelWarn)
+
+	is.NoError(registry.Set("datadog", slog.LevelDebug, 20*time.Millisecond))
+	state, ok := registry.State("datadog")
+	is.True(ok)
+	is.Equal(slog.LevelDebug, state.Level)
+	is.NotNil(state.ExpiresAt)
+
+	is.Eventually(func() bool {
+		state, _ := registry.State("datadog")
+		return state.Level == slog.LevelWarn && state.ExpiresAt == nil
+	}, time.Second, 5*time.Millisecond)
+
+	// a new change cancels the pending revert
+	is.NoError(registry.Set("datadog", slog.LevelDebug, 20*time.Millisecond))
+	is.NoError(registry.Set("datadog", slog.LevelError, 0))
+	time.Sleep(50 * time.Millisecond)
+	state, _ = registry.State("datadog")
+	is.Equal(slog.LevelError, state.Level)
+}
+
+func TestLevelRegistryHTTPHandler(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewLevelRegistry()
+	registry.Register("stdout", slog.LevelInfo)
+	registry.Register("datadog", slog.LevelWarn)
+
+	server := httptest.NewServer(registry.HTTPHandler())
+	defer server.Close()
+
+	do := func(method string, url string, body string) (int, map[string]any, []any) {
+		req, err := http.NewRequest(method, server.URL+url, strings.NewReader(body))
+		is.NoError(err)
+		res, err := server.Client().Do(req)
+		is.NoError(err)
+		defer res.Body.Close()
+
+		var raw json.RawMessage
+		is.NoError(json.NewDecoder(res.Body).Decode(&raw))
+
+		var object map[string]any
+		var list []any
+		if json.Unmarshal(raw, &object) != nil {
+			is.NoError(json.Unmarshal(raw, &list))
+		}
+		return res.StatusCode, object, list
+	}
+
+	status, _, list := do(http.MethodGet, "/", "")
+	is.Equal(http.StatusOK, status)
+	is.Equal([]any{
+		map[string]any{"name": "datadog", "level": "WARN", "default": "WARN"},
+		map[string]any{"name": "stdout", "level": "INFO", "default": "INFO"},
+	}, list)
+
+	status, object, _ := do(http.MethodPut, "/", `{"name":"datadog","level":"DEBUG","ttl":"1h"}`)
+	is.Equal(http.StatusOK, status)
+	is.Equal("DEBUG", object["level"])
+	is.NotEmpty(object["expires_at"])
+	is.Equal(slog.LevelDebug, registry.LevelVar("datadog").Level())
+
+	status, object, _ = do(http.MethodGet, "/?name=datadog", "")
+	is.Equal(http.StatusOK, status)
+	is.Equal("DEBUG", object["level"])
+
+	status, object, _ = do(http.MethodDelete, "/?name=datadog", "")
+	is.Equal(http.StatusOK, status)
+	is.Equal("WARN", object["level"])
+	is.Nil(object["expires_at"])
+
+	status, _, _ = do(http.MethodGet, "/?name=unknown", "")
+	is.Equal(http.StatusNotFound, status)
+	status, _, _ = do(http.MethodPut, "/", `{"name":"unknown","level":"DEBUG"}`)
+	is.Equal(http.StatusNotFound, status)
+	status, _, _ = do(http.MethodPut, "/", `{"name":"stdout","level":"VERBOSE"}`)
+	is.Equal(http.StatusBadRequest, status)
+	status, _, _ = do(http.MethodPut, "/", `{"name":"stdout","level":"DEBUG","ttl":"soon"}`)
+	is.Equal(http.StatusBadRequest, status)
+	status, _, _ = do(http.MethodPatch, "/", "")
+	is.Equal(http.StatusMethodNotAllowed, status)
+}
@@ -0,0 +1,254 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type levelOverrideKey struct{}
+
+// WithLevelOverride returns a copy of ctx lowering the minimum level of every
+// overridable branch to the given level, for records logged with this context only.
+//
+// Example usage:
+//
+//	ctx = slogmulti.WithLevelOverride(ctx, slog.LevelDebug)
+//	logger.DebugContext(ctx, "sql query", "query", query) // emitted, other requests stay at INFO
+func WithLevelOverride(ctx context.Context, level slog.Level) context.Context {
+	return context.WithValue(ctx, levelOverrideKey{}, level)
+}
+
+// WithDebug is a shortcut for WithLevelOverride(ctx, slog.LevelDebug).
+func WithDebug(ctx context.Context) context.Context {
+	return WithLevelOverride(ctx, slog.LevelDebug)
+}
+
+// LevelOverrideFromContext returns the level override stored in ctx, if any.
+func LevelOverrideFromContext(ctx context.Context) (slog.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+
+	level, ok := ctx.Value(levelOverrideKey{}).(slog.Level)
+	return level, ok
+}
+
+// LevelOverrideHTTPOption configures LevelOverrideHTTPMiddleware.
+type LevelOverrideHTTPOption struct {
+	// MinLevel is the lowest level a request can set: lower levels are raised
+	// to MinLevel. Default: slog.LevelDebug.
+	MinLevel slog.Leveler
+	// Trusted decides whether a request may set a level override (eg: internal
+	// network, authenticated operator). Default: every request is trusted.
+	Trusted func(r *http.Request) bool
+}
+
+// LevelOverrideHTTPMiddleware returns a net/http middleware that marks the request
+// context with a level override when the given header is set (eg: "X-Debug").
+// Header values "1", "true" and "on" lower the level to DEBUG. Other values are
+// parsed as a slog.Level ("DEBUG", "INFO-2"...). Invalid values are ignored.
+//
+// The header is set by the HTTP client: unless the server is only reachable by
+// trusted clients, any caller can turn on verbose logging for its requests,
+// increasing the log volume and possibly exposing data that is only logged at
+// debug level. Use Trusted to restrict overrides to trusted requests, and
+// MinLevel to bound the level that can be requested.
+//
+// Example usage:
+//
+//	http.ListenAndServe(":8080", slogmulti.LevelOverrideHTTPMiddleware("X-Debug", slogmulti.LevelOverrideHTTPOption{
+//	    Trusted: func(r *http.Request) bool { return isOperator(r) },
+//	})(mux))
+//
+// Args:
+//
+//	header: The name of the request header carrying the level
+//	opts: The minimum level and the trust policy
+//
+// Returns:
+//
+//	A net/http middleware setting level overrides
+func LevelOverrideHTTPMiddleware(header string, opts LevelOverrideHTTPOption) func(http.Handler) http.Handler {
+	if opts.MinLevel == nil {
+		opts.MinLevel = slog.LevelDebug
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value := strings.TrimSpace(r.Header.Get(header))
+			if value == "" || (opts.Trusted != nil && !opts.Trusted(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var level slog.Level
+			switch strings.ToLower(value) {
+			case "1", "true", "on":
+				level = slog.LevelDebug
+			default:
+				if err := level.UnmarshalText([]byte(value)); err != nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			level = max(level, opts.MinLevel.Level())
+			next.ServeHTTP(w, r.WithContext(WithLevelOverride(r.Context(), level)))
+		})
+	}
+}
+
+// LevelOverridePolicy decides whether a named branch honours level overrides.
+// A nil policy allows every branch. See LevelRegistry.SetOverridePolicy.
+type LevelOverridePolicy func(branch string) bool
+
+// AllowBranches returns a policy allowing overrides only on the given branches.
+func AllowBranches(branches ...string) LevelOverridePolicy {
+	return func(branch string) bool {
+		return slices.Contains(branches, branch)
+	}
+}
+
+// DenyBranches returns a policy allowing overrides on every branch but the given ones.
+func DenyBranches(branches ...string) LevelOverridePolicy {
+	return func(branch string) bool {
+		return !slices.Contains(branches, branch)
+	}
+}
+
+// isLevelOverridden returns true when ctx carries an override accepting the level on the branch.
+func isLevelOverridden(ctx context.Context, policy LevelOverridePolicy, branch string, l slog.Level) bool {
+	override, ok := LevelOverrideFromContext(ctx)
+	if !ok || l < override {
+		return false
+	}
+
+	return policy == nil || policy(branch)
+}
+
+// Ensure LevelOverrideHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*LevelOverrideHandler)(nil)
+
+// LevelOverrideHandler bypasses the level of the next handler when the logging
+// context carries a level override. Other records are gated by the next handler.
+type LevelOverrideHandler struct {
+	// next is the handler receiving records
+	next slog.Handler
+	// branch is the name of the branch, checked against the policy
+	branch string
+	// registry holds the override policy (optional)
+	registry *LevelRegistry
+}
+
+// LevelOverride creates a middleware that lets a context flag (see WithLevelOverride)
+// lower the minimum level of the next handler, for a single request.
+//
+// Since Fanout, Router and their children receive ctx in Enabled, every branch
+// wrapped with LevelOverride honours the flag, while other requests keep the
+// level of the branch. LevelGate branches honour overrides natively.
+//
+// The branches honouring overrides are decided by the override policy of the
+// registry (see LevelRegistry.SetOverridePolicy), shared with LevelGate. With a
+// nil registry, the branch always honours overrides.
+//
+// Example usage:
+//
+//	registry := slogmulti.NewLevelRegistry()
+//	registry.SetOverridePolicy(slogmulti.DenyBranches("datadog"))
+//
+//	handler := slogmulti.Fanout(
+//	    slogmulti.Pipe(slogmulti.LevelOverride(registry, "stdout")).Handler(stdoutHandler),
+//	    slogmulti.Pipe(slogmulti.LevelOverride(registry, "datadog")).Handler(datadogHandler),
+//	)
+//
+// Args:
+//
+//	registry: The registry holding the override policy, or nil
+//	branch: The name of the branch
+//
+// Returns:
+//
+//	A middleware honouring level overrides
+func LevelOverride(registry *LevelRegistry, branch string) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return &LevelOverrideHandler{
+			next:     next,
+			branch:   branch,
+			registry: registry,
+		}
+	}
+}
+
+// Enabled returns true when the context overrides the level, otherwise it checks the next handler.
+// This method implements the slog.Handler interface requirement.
+func (h *LevelOverrideHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.overridden(ctx, l) || h.next.Enabled(ctx, l)
+}
+
+func (h *LevelOverrideHandler) overridden(ctx context.Context, l slog.Level) bool {
+	// avoid locking the registry when the context carries no override
+	if _, ok := LevelOverrideFromContext(ctx); !ok {
+		return false
+	}
+
+	var policy LevelOverridePolicy
+	if h.registry != nil {
+		policy = h.registry.overridePolicy()
+	}
+
+	return isLevelOverridden(ctx, policy, h.branch, l)
+}
+
+// Handle forwards the record to the next handler.
+// This method implements the slog.Handler interface requirement.
+func (h *LevelOverrideHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs creates a new LevelOverrideHandler with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *LevelOverrideHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LevelOverrideHandler{
+		next:     h.next.WithAttrs(attrs),
+		branch:   h.branch,
+		registry: h.registry,
+	}
+}
+
+// WithGroup creates a new LevelOverrideHandler with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *LevelOverrideHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	return &LevelOverrideHandler{
+		next:     h.next.WithGroup(name),
+		branch:   h.branch,
+		registry: h.registry,
+	}
+}
+
+// Flush flushes the next handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *LevelOverrideHandler) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Close closes the next handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *LevelOverrideHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Inspect describes the LevelOverride middleware, its branch name and the next handler.
+// This method implements the Inspector interface.
+func (h *LevelOverrideHandler) Inspect() HandlerNode {
+	node := inspectNode("LevelOverride", nil, nil, h.next)
+	node.Name = h.branch
+	return node
+}
@@ -0,0 +1,124 @@
+package slogmulti
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelOverride(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	stdout := bytes.NewBufferString("")
+	datadog := bytes.NewBufferString("")
+
+	registry := NewLevelRegistry()
+	registry.SetOverridePolicy(DenyBranches("datadog"))
+
+	logger := slog.New(
+		Router().
+			Add(Pipe(LevelOverride(registry, "stdout")).Handler(slog.NewTextHandler(stdout, &slog.HandlerOptions{ReplaceAttr: remoteTimeReplaceAttr}))).
+			Add(Pipe(LevelOverride(registry, "datadog")).Handler(slog.NewTextHandler(datadog, &slog.HandlerOptions{ReplaceAttr: remoteTimeReplaceAttr}))).
+			Handler(),
+	)
+
+	ctx := context.Background()
+	debugCtx := WithDebug(ctx)
+
+	logger.DebugContext(ctx, "hidden")
+	logger.DebugContext(debugCtx, "visible", "foo", "bar")
+	logger.With("a", 1).WithGroup("g").DebugContext(debugCtx, "visible", "foo", "bar")
+	logger.InfoContext(ctx, "info")
+
+	is.Equal("level=DEBUG msg=visible foo=bar\nlevel=DEBUG msg=visible a=1 g.foo=bar\nlevel=INFO msg=info\n", stdout.String())
+	is.Equal("level=INFO msg=info\n", datadog.String())
+
+	// override to a higher level does not hide records
+	is.True(logger.Enabled(WithLevelOverride(ctx, slog.LevelError), slog.LevelInfo))
+
+	// the policy of the registry is read on every record
+	registry.SetOverridePolicy(nil)
+	logger.DebugContext(debugCtx, "allowed")
+	is.Equal("level=INFO msg=info\nlevel=DEBUG msg=allowed\n", datadog.String())
+
+	// without registry, the branch always honours overrides
+	is.True(LevelOverride(nil, "stdout")(&noopHandler{}).Enabled(debugCtx, slog.LevelDebug))
+}
+
+func TestLevelOverrideLevelGate(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewLevelRegistry()
+	registry.Register("stdout", slog.LevelInfo)
+	registry.Register("audit", slog.LevelWarn)
+	registry.SetOverridePolicy(AllowBranches("stdout"))
+
+	stdout := bytes.NewBufferString("")
+	audit := bytes.NewBufferString("")
+
+	logger := slog.New(Fanout(
+		Pipe(LevelGate(registry, "stdout")).Handler(slog.NewTextHandler(stdout, &slog.HandlerOptions{Level: slog.LevelInfo, ReplaceAttr: remoteTimeReplaceAttr})),
+		Pipe(LevelGate(registry, "audit")).Handler(slog.NewTextHandler(audit, &slog.HandlerOptions{Level: slog.LevelInfo, ReplaceAttr: remoteTimeReplaceAttr})),
+	))
+
+	logger.DebugContext(WithDebug(context.Background()), "debug")
+	logger.InfoContext(WithDebug(context.Background()), "info")
+
+	is.Equal("level=DEBUG msg=debug\nlevel=INFO msg=info\n", stdout.String())
+	is.Equal("", audit.String())
+}
+
+func TestLevelOverrideHTTPMiddleware(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var level slog.Level
+	var ok bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		level, ok = LevelOverrideFromContext(r.Context())
+	})
+
+	trusted := func(r *http.Request) bool { return r.Header.Get("X-Operator") == "yes" }
+
+	for _, tc := range []struct {
+		opts     LevelOverrideHTTPOption
+		header   string
+		operator bool
+		ok       bool
+		expected slog.Level
+	}{
+		{header: "", ok: false},
+		{header: "1", ok: true, expected: slog.LevelDebug},
+		{header: "true", ok: true, expected: slog.LevelDebug},
+		{header: "WARN", ok: true, expected: slog.LevelWarn},
+		{header: "nope", ok: false},
+		// levels are bounded by MinLevel
+		{header: "debug-100", ok: true, expected: slog.LevelDebug},
+		{opts: LevelOverrideHTTPOption{MinLevel: slog.LevelDebug - 4}, header: "debug-2", ok: true, expected: slog.LevelDebug - 2},
+		{opts: LevelOverrideHTTPOption{MinLevel: slog.LevelDebug - 4}, header: "debug-100", ok: true, expected: slog.LevelDebug - 4},
+		// untrusted requests are ignored
+		{opts: LevelOverrideHTTPOption{Trusted: trusted}, header: "1", ok: false},
+		{opts: LevelOverrideHTTPOption{Trusted: trusted}, header: "1", operator: true, ok: true, expected: slog.LevelDebug},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.header != "" {
+			req.Header.Set("X-Debug", tc.header)
+		}
+		if tc.operator {
+			req.Header.Set("X-Operator", "yes")
+		}
+		LevelOverrideHTTPMiddleware("X-Debug", tc.opts)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+		is.Equal(tc.ok, ok, tc.header)
+		if tc.ok {
+			is.Equal(tc.expected, level, tc.header)
+		}
+	}
+}
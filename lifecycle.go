@@ -0,0 +1,175 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// Flusher is implemented by handlers buffering records (eg: batching or network sinks).
+// Composite handlers and middlewares of this package implement Flusher by walking
+// their children.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Closer is implemented by handlers holding resources. Close must flush pending records.
+// Composite handlers and middlewares of this package implement Closer by walking
+// their children.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// Ensure composite handlers implement Flusher and Closer at compile time
+var (
+	_ Flusher = (*FanoutHandler)(nil)
+	_ Closer  = (*FanoutHandler)(nil)
+	_ Flusher = (*FailoverHandler)(nil)
+	_ Closer  = (*FailoverHandler)(nil)
+	_ Flusher = (*PoolHandler)(nil)
+	_ Closer  = (*PoolHandler)(nil)
+	_ Flusher = (*FirstMatchHandler)(nil)
+	_ Closer  = (*FirstMatchHandler)(nil)
+	_ Flusher = (*RouterHandler)(nil)
+	_ Closer  = (*RouterHandler)(nil)
+	_ Flusher = (*RoutableHandler)(nil)
+	_ Closer  = (*RoutableHandler)(nil)
+	_ Flusher = (*HandlerErrorRecovery)(nil)
+	_ Closer  = (*HandlerErrorRecovery)(nil)
+	_ Flusher = (*InlineMiddleware)(nil)
+	_ Closer  = (*InlineMiddleware)(nil)
+	_ Flusher = (*EnabledInlineMiddleware)(nil)
+	_ Closer  = (*EnabledInlineMiddleware)(nil)
+	_ Flusher = (*HandleInlineMiddleware)(nil)
+	_ Closer  = (*HandleInlineMiddleware)(nil)
+	_ Flusher = (*WithAttrsInlineMiddleware)(nil)
+	_ Closer  = (*WithAttrsInlineMiddleware)(nil)
+	_ Flusher = (*WithGroupInlineMiddleware)(nil)
+	_ Closer  = (*WithGroupInlineMiddleware)(nil)
+	_ Flusher = (*TransformHandler)(nil)
+	_ Closer  = (*TransformHandler)(nil)
+	_ Flusher = (*ContextAttrsHandler)(nil)
+	_ Closer  = (*ContextAttrsHandler)(nil)
+	_ Flusher = (*ErrorFormatterHandler)(nil)
+	_ Closer  = (*ErrorFormatterHandler)(nil)
+	_ Flusher = (*LevelGateHandler)(nil)
+	_ Closer  = (*LevelGateHandler)(nil)
+	_ Flusher = (*LevelOverrideHandler)(nil)
+	_ Closer  = (*LevelOverrideHandler)(nil)
+	_ Flusher = (*ConditionalHandler)(nil)
+	_ Closer  = (*ConditionalHandler)(nil)
+	_ Flusher = (*TeeHandler)(nil)
+	_ Closer  = (*TeeHandler)(nil)
+	_ Flusher = (*BatchHandler)(nil)
+	_ Closer  = (*BatchHandler)(nil)
+	_ Flusher = (*AggregateHandler)(nil)
+	_ Closer  = (*AggregateHandler)(nil)
+	_ Flusher = (*MetricsHandler)(nil)
+	_ Closer  = (*MetricsHandler)(nil)
+)
+
+// Flush flushes a handler tree: every handler implementing Flusher (or a
+// `Flush() error` method) is flushed. Errors are joined.
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//	handler: The root of the handler tree
+//
+// Returns:
+//
+//	The joined errors of the flushed handlers
+func Flush(ctx context.Context, handler slog.Handler) error {
+	return flushHandler(ctx, handler)
+}
+
+// Shutdown flushes and closes a handler tree, typically before the program exits.
+// Every handler implementing Closer (or io.Closer) is closed, and handlers only
+// implementing Flusher are flushed. Errors are joined.
+//
+// Shutdown returns ctx.Err() when the deadline is exceeded before completion.
+//
+// Example usage:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//
+//	if err := slogmulti.Shutdown(ctx, handler); err != nil {
+//	    log.Println(err)
+//	}
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//	handler: The root of the handler tree
+//
+// Returns:
+//
+//	The joined errors of the closed handlers, or ctx.Err()
+func Shutdown(ctx context.Context, handler slog.Handler) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- closeHandler(ctx, handler)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func flushHandler(ctx context.Context, handler slog.Handler) error {
+	switch h := handler.(type) {
+	case Flusher:
+		return try(func() error { return h.Flush(ctx) })
+	case interface{ Flush() error }:
+		return try(h.Flush)
+	}
+
+	return nil
+}
+
+func closeHandler(ctx context.Context, handler slog.Handler) error {
+	switch h := handler.(type) {
+	case Closer:
+		return try(func() error { return h.Close(ctx) })
+	case io.Closer:
+		return try(h.Close)
+	}
+
+	return flushHandler(ctx, handler)
+}
+
+// flushHandlers flushes children in order, until the context is done.
+func flushHandlers[T slog.Handler](ctx context.Context, handlers ...T) error {
+	var errs []error
+	for _, handler := range handlers {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := flushHandler(ctx, handler); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// closeHandlers closes children in order, until the context is done.
+func closeHandlers[T slog.Handler](ctx context.Context, handlers ...T) error {
+	var errs []error
+	for _, handler := range handlers {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := closeHandler(ctx, handler); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lifecycleHandler struct {
+	noopHandler
+	flushCount atomic.Int64
+	closeCount atomic.Int64
+	err        error
+	release    chan struct{}
+}
+
+func (h *lifecycleHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *lifecycleHandler) WithGroup(string) slog.Handler      { return h }
+
+func (h *lifecycleHandler) Flush(ctx context.Context) error {
+	h.flushCount.Add(1)
+	return h.err
+}
+
+func (h *lifecycleHandler) Close(ctx context.Context) error {
+	if h.release != nil {
+		<-h.release
+	}
+	h.closeCount.Add(1)
+	return h.err
+}
+
+// legacyFlushHandler only implements `Flush() error`.
+type legacyFlushHandler struct {
+	noopHandler
+	flushCount atomic.Int64
+}
+
+func (h *legacyFlushHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *legacyFlushHandler) WithGroup(string) slog.Handler      { return h }
+
+func (h *legacyFlushHandler) Flush() error {
+	h.flushCount.Add(1)
+	return nil
+}
+
+func TestShutdownWalksHandlerTree(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	h1, h2, h3, h4, h5, h6 := &lifecycleHandler{}, &lifecycleHandler{}, &lifecycleHandler{}, &lifecycleHandler{}, &lifecycleHandler{}, &legacyFlushHandler{}
+	passthrough := NewHandleInlineMiddleware(func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+		return next(ctx, record)
+	})
+
+	handler := Fanout(
+		Failover()(h1, Pipe(RecoverHandlerError(func(context.Context, slog.Record, error) {})).Handler(h2)),
+		Pool()(Pipe(passthrough, Transform(), LevelOverride(nil, "h3")).Handler(h3)),
+		Router().Add(h4, LevelIs(slog.LevelError)).FirstMatch().Handler(),
+		Router().Add(h5).Handler(),
+		h6,
+	).WithAttrs([]slog.Attr{slog.String("foo", "bar")}).WithGroup("group")
+
+	is.NoError(Flush(context.Background(), handler))
+	for _, h := range []*lifecycleHandler{h1, h2, h3, h4, h5} {
+		is.EqualValues(1, h.flushCount.Load())
+		is.EqualValues(0, h.closeCount.Load())
+	}
+	is.EqualValues(1, h6.flushCount.Load())
+
+	is.NoError(Shutdown(context.Background(), handler))
+	for _, h := range []*lifecycleHandler{h1, h2, h3, h4, h5} {
+		is.EqualValues(1, h.flushCount.Load())
+		is.EqualValues(1, h.closeCount.Load())
+	}
+	// handlers without Close are flushed on shutdown
+	is.EqualValues(2, h6.flushCount.Load())
+}
+
+func TestShutdownJoinsErrors(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+
+	handler := Fanout(&lifecycleHandler{err: err1}, &lifecycleHandler{}, &lifecycleHandler{err: err2})
+
+	err := Shutdown(context.Background(), handler)
+	is.ErrorIs(err, err1)
+	is.ErrorIs(err, err2)
+
+	err = Flush(context.Background(), handler)
+	is.ErrorIs(err, err1)
+	is.ErrorIs(err, err2)
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	slow := &lifecycleHandler{release: make(chan struct{})}
+	next := &lifecycleHandler{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := Shutdown(ctx, Fanout(slow, next))
+	is.ErrorIs(err, context.DeadlineExceeded)
+
+	// the remaining handlers are not closed once the deadline is exceeded
+	close(slow.release)
+	is.Eventually(func() bool { return slow.closeCount.Load() == 1 }, time.Second, time.Millisecond)
+	is.EqualValues(0, next.closeCount.Load())
+}
@@ -0,0 +1,563 @@
+package slogmulti
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	slogcommon "github.com/samber/slog-common"
+	"github.com/samber/slog-multi/internal/slogattr"
+)
+
+// MetricsOption configures a MetricsRegistry.
+type MetricsOption struct {
+	// Namespace prefixes the metric names. Default: "slog".
+	Namespace string
+	// Attrs are the dotted paths of the attributes whose values label the
+	// record counters (eg: "service", "http.route"). Their label names, where
+	// characters other than letters, digits and underscores are replaced by
+	// underscores, must be unique and differ from "handler", "level" and "msg".
+	// Default: none.
+	Attrs []string
+	// MaxSeries caps the number of record counters, since messages and
+	// attribute values may be unbounded. Records of new series beyond the cap
+	// are counted with the "_other" message and attribute values. Default: 1000.
+	MaxSeries int
+}
+
+// metricsOverflow replaces the message and attribute values of the series beyond MaxSeries.
+const metricsOverflow = "_other"
+
+// MetricsRegistry holds the counters of the Metrics middlewares, one per
+// wrapped handler (eg: "stdout", "datadog"). Counters are exposed with
+// Snapshot, and in Prometheus text exposition format with HTTPHandler.
+type MetricsRegistry struct {
+	opts MetricsOption
+	// labels are the Prometheus label names of opts.Attrs
+	labels []string
+
+	mu       sync.RWMutex
+	records  map[string]*recordSeries
+	handlers map[string]*handlerCounters
+}
+
+type recordSeries struct {
+	handler string
+	level   slog.Level
+	message string
+	attrs   []string
+	count   atomic.Int64
+}
+
+type handlerCounters struct {
+	errors   atomic.Int64
+	panics   atomic.Int64
+	dropped  atomic.Int64
+	filtered atomic.Int64
+}
+
+// MetricsSnapshot is a point-in-time copy of the counters of a MetricsRegistry.
+type MetricsSnapshot struct {
+	// Records are sorted by handler, level, message and attribute values.
+	Records []RecordCount `json:"records"`
+	// Handlers are sorted by name.
+	Handlers []HandlerStats `json:"handlers"`
+}
+
+// RecordCount is the number of records of a handler sharing the same level,
+// message and attribute values.
+type RecordCount struct {
+	Handler string            `json:"handler"`
+	Level   slog.Level        `json:"level"`
+	Message string            `json:"msg"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+	Count   int64             `json:"count"`
+}
+
+// HandlerStats are the failure counters of a wrapped handler.
+type HandlerStats struct {
+	Name string `json:"name"`
+	// Errors is the number of records whose Handle returned an error.
+	Errors int64 `json:"errors"`
+	// Panics is the number of records whose Handle panicked.
+	Panics int64 `json:"panics"`
+	// Dropped is the number of records which did not reach the handler because
+	// of a failure: errors and panics.
+	Dropped int64 `json:"dropped"`
+	// Filtered is the number of records refused by Enabled when handled (eg: the
+	// level of the handler changed in the meantime). They are not failures.
+	Filtered int64 `json:"filtered"`
+}
+
+// Count returns the number of records of a handler at the given level. An
+// empty handler name counts the records of every handler.
+func (s MetricsSnapshot) Count(handler string, level slog.Level) int64 {
+	count := int64(0)
+	for _, record := range s.Records {
+		if (handler == "" || record.Handler == handler) && record.Level == level {
+			count += record.Count
+		}
+	}
+	return count
+}
+
+// Handler returns the failure counters of a handler.
+func (s MetricsSnapshot) Handler(name string) (HandlerStats, bool) {
+	for _, stats := range s.Handlers {
+		if stats.Name == name {
+			return stats, true
+		}
+	}
+	return HandlerStats{}, false
+}
+
+// NewMetricsRegistry creates an empty MetricsRegistry. It panics when the label
+// names of opts.Attrs collide, with each other or with the built-in labels.
+func NewMetricsRegistry(opts MetricsOption) *MetricsRegistry {
+	if opts.Namespace == "" {
+		opts.Namespace = "slog"
+	}
+	if opts.MaxSeries <= 0 {
+		opts.MaxSeries = 1000
+	}
+
+	// the built-in labels of the record counters
+	seen := map[string]string{"handler": "", "level": "", "msg": ""}
+
+	labels := make([]string, 0, len(opts.Attrs))
+	for _, path := range opts.Attrs {
+		label := prometheusName(path)
+		if label == "" || strings.HasPrefix(label, "__") {
+			panic(fmt.Sprintf("slog-multi: invalid metrics label %q for attribute %q", label, path))
+		}
+		if other, ok := seen[label]; ok {
+			if other == "" {
+				panic(fmt.Sprintf("slog-multi: metrics label %q of attribute %q collides with a built-in label", label, path))
+			}
+			panic(fmt.Sprintf("slog-multi: metrics label %q of attribute %q collides with attribute %q", label, path, other))
+		}
+
+		seen[label] = path
+		labels = append(labels, label)
+	}
+
+	return &MetricsRegistry{
+		opts:     opts,
+		labels:   labels,
+		records:  map[string]*recordSeries{},
+		handlers: map[string]*handlerCounters{},
+	}
+}
+
+// handler returns the failure counters of a named handler, registering them when missing.
+func (m *MetricsRegistry) handler(name string) *handlerCounters {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters, ok := m.handlers[name]
+	if !ok {
+		counters = &handlerCounters{}
+		m.handlers[name] = counters
+	}
+	return counters
+}
+
+// series returns the counter of a series, registering it when missing.
+func (m *MetricsRegistry) series(handler string, level slog.Level, message string, attrs []string) *recordSeries {
+	key := seriesKey(handler, level, message, attrs)
+
+	m.mu.RLock()
+	series, ok := m.records[key]
+	m.mu.RUnlock()
+	if ok {
+		return series
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if series, ok := m.records[key]; ok {
+		return series
+	}
+
+	if len(m.records) >= m.opts.MaxSeries {
+		message = metricsOverflow
+		attrs = make([]string, len(attrs))
+		for i := range attrs {
+			attrs[i] = metricsOverflow
+		}
+		key = seriesKey(handler, level, message, attrs)
+		if series, ok := m.records[key]; ok {
+			return series
+		}
+	}
+
+	series = &recordSeries{
+		handler: handler,
+		level:   level,
+		message: message,
+		attrs:   attrs,
+	}
+	m.records[key] = series
+	return series
+}
+
+func seriesKey(handler string, level slog.Level, message string, attrs []string) string {
+	var key strings.Builder
+	key.WriteString(handler)
+	key.WriteByte(0)
+	key.WriteString(level.String())
+	key.WriteByte(0)
+	key.WriteString(message)
+	for _, value := range attrs {
+		key.WriteByte(0)
+		key.WriteString(value)
+	}
+	return key.String()
+}
+
+// Snapshot returns a copy of the counters.
+func (m *MetricsRegistry) Snapshot() MetricsSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := MetricsSnapshot{
+		Records:  make([]RecordCount, 0, len(m.records)),
+		Handlers: make([]HandlerStats, 0, len(m.handlers)),
+	}
+
+	for _, series := range m.sortedSeries() {
+		var attrs map[string]string
+		if len(m.opts.Attrs) > 0 {
+			attrs = make(map[string]string, len(m.opts.Attrs))
+			for i, path := range m.opts.Attrs {
+				attrs[path] = series.attrs[i]
+			}
+		}
+
+		snapshot.Records = append(snapshot.Records, RecordCount{
+			Handler: series.handler,
+			Level:   series.level,
+			Message: series.message,
+			Attrs:   attrs,
+			Count:   series.count.Load(),
+		})
+	}
+
+	for _, name := range m.sortedHandlers() {
+		counters := m.handlers[name]
+		snapshot.Handlers = append(snapshot.Handlers, HandlerStats{
+			Name:     name,
+			Errors:   counters.errors.Load(),
+			Panics:   counters.panics.Load(),
+			Dropped:  counters.dropped.Load(),
+			Filtered: counters.filtered.Load(),
+		})
+	}
+
+	return snapshot
+}
+
+// sortedSeries must be called with the lock held.
+func (m *MetricsRegistry) sortedSeries() []*recordSeries {
+	series := make([]*recordSeries, 0, len(m.records))
+	for _, s := range m.records {
+		series = append(series, s)
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		a, b := series[i], series[j]
+		if a.handler != b.handler {
+			return a.handler < b.handler
+		}
+		if a.level != b.level {
+			return a.level < b.level
+		}
+		if a.message != b.message {
+			return a.message < b.message
+		}
+		return strings.Join(a.attrs, "\x00") < strings.Join(b.attrs, "\x00")
+	})
+
+	return series
+}
+
+// sortedHandlers must be called with the lock held.
+func (m *MetricsRegistry) sortedHandlers() []string {
+	names := make([]string, 0, len(m.handlers))
+	for name := range m.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WritePrometheus writes the counters in Prometheus text exposition format:
+//
+//	slog_records_total{handler="datadog",level="ERROR",msg="payment failed"} 3
+//	slog_handler_errors_total{handler="datadog"} 1
+//	slog_handler_panics_total{handler="datadog"} 0
+//	slog_handler_dropped_total{handler="datadog"} 1
+//	slog_handler_filtered_total{handler="datadog"} 0
+func (m *MetricsRegistry) WritePrometheus(w io.Writer) error {
+	snapshot := m.Snapshot()
+	ns := prometheusName(m.opts.Namespace)
+
+	buf := bufio.NewWriter(w)
+
+	name := ns + "_records_total"
+	fmt.Fprintf(buf, "# HELP %s Records logged, by handler, level, message and attributes.\n", name)
+	fmt.Fprintf(buf, "# TYPE %s counter\n", name)
+	for _, record := range snapshot.Records {
+		labels := []string{
+			prometheusLabel("handler", record.Handler),
+			prometheusLabel("level", record.Level.String()),
+			prometheusLabel("msg", record.Message),
+		}
+		for i, path := range m.opts.Attrs {
+			labels = append(labels, prometheusLabel(m.labels[i], record.Attrs[path]))
+		}
+		fmt.Fprintf(buf, "%s{%s} %d\n", name, strings.Join(labels, ","), record.Count)
+	}
+
+	counters := []struct {
+		suffix string
+		help   string
+		value  func(stats HandlerStats) int64
+	}{
+		{"_handler_errors_total", "Records whose handler returned an error.", func(s HandlerStats) int64 { return s.Errors }},
+		{"_handler_panics_total", "Records whose handler panicked.", func(s HandlerStats) int64 { return s.Panics }},
+		{"_handler_dropped_total", "Records which did not reach their handler, because of an error or a panic.", func(s HandlerStats) int64 { return s.Dropped }},
+		{"_handler_filtered_total", "Records refused by the level of their handler.", func(s HandlerStats) int64 { return s.Filtered }},
+	}
+	for _, counter := range counters {
+		name := ns + counter.suffix
+		fmt.Fprintf(buf, "# HELP %s %s\n", name, counter.help)
+		fmt.Fprintf(buf, "# TYPE %s counter\n", name)
+		for _, stats := range snapshot.Handlers {
+			fmt.Fprintf(buf, "%s{%s} %d\n", name, prometheusLabel("handler", stats.Name), counter.value(stats))
+		}
+	}
+
+	return buf.Flush()
+}
+
+// HTTPHandler returns an http.Handler serving the counters in Prometheus text
+// exposition format, to be scraped by Prometheus or a compatible agent.
+//
+// Example usage:
+//
+//	http.Handle("/metrics/logs", registry.HTTPHandler())
+func (m *MetricsRegistry) HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "slog-multi: method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = m.WritePrometheus(w)
+	})
+}
+
+// prometheusName converts a name to a valid Prometheus metric or label name:
+// invalid characters (eg: dots of attribute paths) are replaced by underscores.
+func prometheusName(name string) string {
+	var output strings.Builder
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			output.WriteRune(c)
+		case c >= '0' && c <= '9' && i > 0:
+			output.WriteRune(c)
+		default:
+			output.WriteByte('_')
+		}
+	}
+	return output.String()
+}
+
+var prometheusEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func prometheusLabel(name string, value string) string {
+	return name + `="` + prometheusEscaper.Replace(value) + `"`
+}
+
+// Ensure MetricsHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*MetricsHandler)(nil)
+
+// MetricsHandler counts the records forwarded to the next handler, and its failures.
+type MetricsHandler struct {
+	// next is the measured handler
+	next     slog.Handler
+	name     string
+	registry *MetricsRegistry
+	counters *handlerCounters
+
+	// groups and attrs are tracked to look up the labelling attributes
+	groups []string
+	attrs  []slog.Attr
+}
+
+// Metrics creates a middleware counting the records forwarded to the next
+// handler by level, message and the attribute values selected in the registry
+// options, and counting the errors, panics, dropped and filtered records of the
+// next handler. Several middlewares, one per branch, can share the same registry.
+//
+// Panics of the next handler are counted, then propagated: use RecoverHandlerError
+// or Fanout to recover them.
+//
+// Example usage:
+//
+//	registry := slogmulti.NewMetricsRegistry(slogmulti.MetricsOption{Attrs: []string{"service"}})
+//
+//	handler := slogmulti.Fanout(
+//	    slogmulti.Pipe(slogmulti.Metrics(registry, "stdout")).Handler(stdoutHandler),
+//	    slogmulti.Pipe(slogmulti.Metrics(registry, "datadog")).Handler(datadogHandler),
+//	)
+//
+//	http.Handle("/metrics/logs", registry.HTTPHandler())
+//
+// Args:
+//
+//	registry: The registry holding the counters
+//	name: The name of the next handler
+//
+// Returns:
+//
+//	A middleware measuring the next handler
+func Metrics(registry *MetricsRegistry, name string) Middleware {
+	if registry == nil {
+		panic("slog-multi: registry is required")
+	}
+
+	counters := registry.handler(name)
+
+	return func(next slog.Handler) slog.Handler {
+		if next == nil {
+			panic("slog-multi: next is required")
+		}
+
+		return &MetricsHandler{
+			next:     next,
+			name:     name,
+			registry: registry,
+			counters: counters,
+			groups:   []string{},
+			attrs:    []slog.Attr{},
+		}
+	}
+}
+
+// Enabled checks if the next handler is enabled for the given log level.
+// This method implements the slog.Handler interface requirement.
+func (h *MetricsHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle counts the record, then forwards it to the next handler and counts its failures.
+// This method implements the slog.Handler interface requirement.
+func (h *MetricsHandler) Handle(ctx context.Context, r slog.Record) (err error) {
+	h.count(r)
+
+	if !h.next.Enabled(ctx, r.Level) {
+		h.counters.filtered.Add(1)
+		return nil
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			h.counters.panics.Add(1)
+			h.counters.dropped.Add(1)
+			panic(e)
+		}
+	}()
+
+	err = h.next.Handle(ctx, r)
+	if err != nil {
+		h.counters.errors.Add(1)
+		h.counters.dropped.Add(1)
+	}
+
+	return err
+}
+
+func (h *MetricsHandler) count(r slog.Record) {
+	paths := h.registry.opts.Attrs
+
+	var values []string
+	if len(paths) > 0 {
+		attrs := mergeRecordAttrs(h.attrs, h.groups, &r)
+		values = make([]string, len(paths))
+		for i, path := range paths {
+			if attr, ok := slogattr.FindByPath(attrs, slogattr.SplitPath(path)); ok {
+				values[i] = attr.Value.Resolve().String()
+			}
+		}
+	}
+
+	h.registry.series(h.name, r.Level, r.Message, values).count.Add(1)
+}
+
+// WithAttrs creates a new MetricsHandler with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *MetricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &MetricsHandler{
+		next:     h.next.WithAttrs(attrs),
+		name:     h.name,
+		registry: h.registry,
+		counters: h.counters,
+		groups:   h.groups,
+		attrs:    slogcommon.AppendAttrsToGroup(h.groups, h.attrs, attrs...),
+	}
+}
+
+// WithGroup creates a new MetricsHandler with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *MetricsHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, 0, len(h.groups)+1)
+	groups = append(groups, h.groups...)
+	groups = append(groups, name)
+
+	return &MetricsHandler{
+		next:     h.next.WithGroup(name),
+		name:     h.name,
+		registry: h.registry,
+		counters: h.counters,
+		groups:   groups,
+		attrs:    h.attrs,
+	}
+}
+
+// Flush flushes the next handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *MetricsHandler) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Close closes the next handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *MetricsHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Inspect describes the Metrics middleware, the name of the measured handler and the next handler.
+// This method implements the Inspector interface.
+func (h *MetricsHandler) Inspect() HandlerNode {
+	node := inspectNode("Metrics", nil, nil, h.next)
+	node.Name = h.name
+	return node
+}
@@ -0,0 +1,224 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samber/slog-multi/slogmultitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_RecordCounts(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewMetricsRegistry(MetricsOption{Attrs: []string{"service", "http.route"}})
+	recorder := slogmultitest.NewRecordingHandler(slog.LevelInfo)
+	logger := slog.New(Pipe(Metrics(registry, "stdout")).Handler(recorder))
+
+	api := logger.With("service", "api")
+	api.Error("payment failed")
+	api.Error("payment failed")
+	api.WithGroup("http").Info("request", "route", "/users")
+	logger.Info("request")
+	logger.Debug("ignored")
+
+	is.Equal(4, recorder.Len())
+
+	snapshot := registry.Snapshot()
+	is.Equal([]RecordCount{
+		{Handler: "stdout", Level: slog.LevelInfo, Message: "request", Attrs: map[string]string{"service": "", "http.route": ""}, Count: 1},
+		{Handler: "stdout", Level: slog.LevelInfo, Message: "request", Attrs: map[string]string{"service": "api", "http.route": "/users"}, Count: 1},
+		{Handler: "stdout", Level: slog.LevelError, Message: "payment failed", Attrs: map[string]string{"service": "api", "http.route": ""}, Count: 2},
+	}, snapshot.Records)
+	is.Equal([]HandlerStats{{Name: "stdout"}}, snapshot.Handlers)
+
+	is.Equal(int64(2), snapshot.Count("stdout", slog.LevelError))
+	is.Equal(int64(2), snapshot.Count("", slog.LevelInfo))
+	is.Equal(int64(0), snapshot.Count("datadog", slog.LevelError))
+}
+
+func TestMetrics_HandlerFailures(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewMetricsRegistry(MetricsOption{})
+	failing := slogmultitest.NewFailingHandler(errors.New("unavailable"), slogmultitest.Calls(2), nil)
+	panicking := slogmultitest.NewPanickingHandler("boom", slogmultitest.Calls(1), nil)
+	gated := NewLevelRegistry()
+	gated.Register("audit", slog.LevelInfo)
+
+	handlers := map[string]slog.Handler{
+		"datadog": Pipe(Metrics(registry, "datadog")).Handler(failing),
+		"sentry":  Pipe(Metrics(registry, "sentry")).Handler(panicking),
+		"audit":   Pipe(Metrics(registry, "audit"), LevelGate(gated, "audit")).Handler(&noopHandler{}),
+	}
+	_ = Metrics(registry, "unused")
+
+	ctx := context.Background()
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+
+	is.NoError(handlers["datadog"].Handle(ctx, record))
+	is.EqualError(handlers["datadog"].Handle(ctx, record), "unavailable")
+
+	// panics are counted, then propagated
+	is.PanicsWithValue("boom", func() { _ = handlers["sentry"].Handle(ctx, record) })
+	is.NoError(handlers["sentry"].Handle(ctx, record))
+
+	// the level changed between Enabled and Handle
+	is.NoError(gated.Set("audit", slog.LevelError, 0))
+	is.NoError(handlers["audit"].Handle(ctx, record))
+
+	snapshot := registry.Snapshot()
+	is.Equal([]HandlerStats{
+		{Name: "audit", Filtered: 1},
+		{Name: "datadog", Errors: 1, Dropped: 1},
+		{Name: "sentry", Panics: 1, Dropped: 1},
+		{Name: "unused"},
+	}, snapshot.Handlers)
+	is.Equal(int64(5), snapshot.Count("", slog.LevelInfo))
+}
+
+func TestMetrics_MaxSeries(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewMetricsRegistry(MetricsOption{Attrs: []string{"user"}, MaxSeries: 2})
+	logger := slog.New(Pipe(Metrics(registry, "stdout")).Handler(&noopHandler{}))
+
+	for _, user := range []string{"alice", "bob", "carol", "dave", "alice"} {
+		logger.Info("login", "user", user)
+	}
+
+	snapshot := registry.Snapshot()
+	is.Equal([]RecordCount{
+		{Handler: "stdout", Level: slog.LevelInfo, Message: metricsOverflow, Attrs: map[string]string{"user": metricsOverflow}, Count: 2},
+		{Handler: "stdout", Level: slog.LevelInfo, Message: "login", Attrs: map[string]string{"user": "alice"}, Count: 2},
+		{Handler: "stdout", Level: slog.LevelInfo, Message: "login", Attrs: map[string]string{"user": "bob"}, Count: 1},
+	}, snapshot.Records)
+}
+
+func TestMetrics_Prometheus(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewMetricsRegistry(MetricsOption{Namespace: "app.logs", Attrs: []string{"http.route"}})
+	failing := slogmultitest.NewFailingHandler(errors.New("unavailable"), slogmultitest.Always(), nil)
+	logger := slog.New(Pipe(Metrics(registry, "datadog")).Handler(failing))
+
+	logger.Error("query \"users\" failed\nretrying", slog.Group("http", slog.String("route", `C:\tmp`)))
+
+	server := httptest.NewServer(registry.HTTPHandler())
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	is.NoError(err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	is.NoError(err)
+
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("text/plain; version=0.0.4; charset=utf-8", res.Header.Get("Content-Type"))
+	is.Equal(strings.Join([]string{
+		"# HELP app_logs_records_total Records logged, by handler, level, message and attributes.",
+		"# TYPE app_logs_records_total counter",
+		`app_logs_records_total{handler="datadog",level="ERROR",msg="query \"users\" failed\nretrying",http_route="C:\\tmp"} 1`,
+		"# HELP app_logs_handler_errors_total Records whose handler returned an error.",
+		"# TYPE app_logs_handler_errors_total counter",
+		`app_logs_handler_errors_total{handler="datadog"} 1`,
+		"# HELP app_logs_handler_panics_total Records whose handler panicked.",
+		"# TYPE app_logs_handler_panics_total counter",
+		`app_logs_handler_panics_total{handler="datadog"} 0`,
+		"# HELP app_logs_handler_dropped_total Records which did not reach their handler, because of an error or a panic.",
+		"# TYPE app_logs_handler_dropped_total counter",
+		`app_logs_handler_dropped_total{handler="datadog"} 1`,
+		"# HELP app_logs_handler_filtered_total Records refused by the level of their handler.",
+		"# TYPE app_logs_handler_filtered_total counter",
+		`app_logs_handler_filtered_total{handler="datadog"} 0`,
+		"",
+	}, "\n"), string(body))
+
+	res, err = http.Post(server.URL, "text/plain", nil)
+	is.NoError(err)
+	res.Body.Close()
+	is.Equal(http.StatusMethodNotAllowed, res.StatusCode)
+}
+
+func TestMetrics_LabelCollisions(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.PanicsWithValue(`slog-multi: metrics label "level" of attribute "level" collides with a built-in label`, func() {
+		NewMetricsRegistry(MetricsOption{Attrs: []string{"level"}})
+	})
+	is.PanicsWithValue(`slog-multi: metrics label "msg" of attribute "msg" collides with a built-in label`, func() {
+		NewMetricsRegistry(MetricsOption{Attrs: []string{"service", "msg"}})
+	})
+	is.PanicsWithValue(`slog-multi: metrics label "handler" of attribute "handler" collides with a built-in label`, func() {
+		NewMetricsRegistry(MetricsOption{Attrs: []string{"handler"}})
+	})
+	is.PanicsWithValue(`slog-multi: metrics label "http_route" of attribute "http_route" collides with attribute "http.route"`, func() {
+		NewMetricsRegistry(MetricsOption{Attrs: []string{"http.route", "http_route"}})
+	})
+	is.PanicsWithValue(`slog-multi: metrics label "service" of attribute "service" collides with attribute "service"`, func() {
+		NewMetricsRegistry(MetricsOption{Attrs: []string{"service", "service"}})
+	})
+	is.PanicsWithValue(`slog-multi: invalid metrics label "" for attribute ""`, func() {
+		NewMetricsRegistry(MetricsOption{Attrs: []string{""}})
+	})
+	is.PanicsWithValue(`slog-multi: invalid metrics label "__name__" for attribute "__name__"`, func() {
+		NewMetricsRegistry(MetricsOption{Attrs: []string{"__name__"}})
+	})
+
+	// labels are case sensitive, and nested attributes do not collide with built-in labels
+	is.NotPanics(func() {
+		NewMetricsRegistry(MetricsOption{Attrs: []string{"Level", "http.level", "user.msg"}})
+	})
+}
+
+func TestMetrics_Concurrent(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	registry := NewMetricsRegistry(MetricsOption{Attrs: []string{"worker"}})
+	logger := slog.New(Pipe(Metrics(registry, "stdout")).Handler(&noopHandler{}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info("tick", "worker", worker%2)
+				_ = registry.Snapshot()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	snapshot := registry.Snapshot()
+	is.Len(snapshot.Records, 2)
+	is.Equal(int64(800), snapshot.Count("stdout", slog.LevelInfo))
+}
+
+func TestMetrics_Inspect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	is.PanicsWithValue("slog-multi: registry is required", func() { Metrics(nil, "stdout") })
+
+	handler := Metrics(NewMetricsRegistry(MetricsOption{}), "stdout")(&noopHandler{})
+	node := Inspect(handler.WithGroup("http"))
+
+	is.Equal("Metrics", node.Type)
+	is.Equal("stdout", node.Name)
+	is.Len(node.Children, 1)
+}
@@ -84,3 +84,18 @@ func (h *InlineMiddleware) WithGroup(name string) slog.Handler {
 		h.withGroupFunc,
 	)(h.withGroupFunc(name, h.next.WithGroup))
 }
+
+// Implements slogmulti.Flusher
+func (h *InlineMiddleware) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Closer
+func (h *InlineMiddleware) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Inspector
+func (h *InlineMiddleware) Inspect() HandlerNode {
+	return inspectNode("InlineMiddleware", nil, nil, h.next)
+}
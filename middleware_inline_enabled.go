@@ -55,3 +55,18 @@ func (h *EnabledInlineMiddleware) WithGroup(name string) slog.Handler {
 
 	return NewEnabledInlineMiddleware(h.enabledFunc)(h.next.WithGroup(name))
 }
+
+// Implements slogmulti.Flusher
+func (h *EnabledInlineMiddleware) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Closer
+func (h *EnabledInlineMiddleware) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Inspector
+func (h *EnabledInlineMiddleware) Inspect() HandlerNode {
+	return inspectNode("EnabledInlineMiddleware", nil, nil, h.next)
+}
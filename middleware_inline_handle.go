@@ -54,3 +54,18 @@ func (h *HandleInlineMiddleware) WithGroup(name string) slog.Handler {
 
 	return NewHandleInlineMiddleware(h.handleFunc)(h.next.WithGroup(name))
 }
+
+// Implements slogmulti.Flusher
+func (h *HandleInlineMiddleware) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Closer
+func (h *HandleInlineMiddleware) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Inspector
+func (h *HandleInlineMiddleware) Inspect() HandlerNode {
+	return inspectNode("HandleInlineMiddleware", nil, nil, h.next)
+}
@@ -0,0 +1,197 @@
+package slogmulti
+
+import (
+	"context"
+	"fmt"
+
+	"log/slog"
+)
+
+// InlineOption configures an inline handler built with NewInlineHandlerWithOptions
+// or an inline middleware built with NewInlineMiddlewareWithOptions.
+// Every hook is optional and defaults to pass-through.
+type InlineOption func(*inlineOptions)
+
+type inlineOptions struct {
+	minLevel slog.Leveler
+
+	// handler hooks
+	enabled func(ctx context.Context, level slog.Level, attrs []slog.Attr) bool
+	handle  func(ctx context.Context, record slog.Record, attrs []slog.Attr) error
+
+	// middleware hooks
+	enabledNext   func(ctx context.Context, level slog.Level, next func(context.Context, slog.Level) bool) bool
+	handleNext    func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error
+	withAttrsNext func(attrs []slog.Attr, next func([]slog.Attr) slog.Handler) slog.Handler
+	withGroupNext func(name string, next func(string) slog.Handler) slog.Handler
+
+	// names of the options, used to reject options of the wrong kind
+	handlerOptions    []string
+	middlewareOptions []string
+}
+
+func newInlineOptions(opts []InlineOption) *inlineOptions {
+	options := &inlineOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(options)
+		}
+	}
+	return options
+}
+
+// enabledLevel reports whether the level reaches the minimum level, if any.
+func (o *inlineOptions) enabledLevel(level slog.Level) bool {
+	return o.minLevel == nil || level >= o.minLevel.Level()
+}
+
+// WithMinLevel drops records below the given level. Since slog.Leveler is
+// accepted, a *slog.LevelVar can be used to change the level at runtime.
+// It applies to both inline handlers and inline middlewares, and is combined
+// with the OnEnabled and OnEnabledNext hooks.
+func WithMinLevel(level slog.Leveler) InlineOption {
+	return func(o *inlineOptions) {
+		o.minLevel = level
+	}
+}
+
+// OnEnabled sets the `Enabled` hook of an inline handler. attrs are the
+// attributes accumulated with WithAttrs, nested under their groups and resolved.
+// Defaults to true.
+func OnEnabled(fn func(ctx context.Context, level slog.Level, attrs []slog.Attr) bool) InlineOption {
+	return func(o *inlineOptions) {
+		o.enabled = fn
+		o.handlerOptions = append(o.handlerOptions, "OnEnabled")
+	}
+}
+
+// OnHandle sets the `Handle` hook of an inline handler. attrs are the
+// accumulated attributes merged with the record attributes, nested under their
+// groups, with slog.LogValuer values resolved, empty attributes removed and
+// groups without key inlined. Defaults to a no-op.
+func OnHandle(fn func(ctx context.Context, record slog.Record, attrs []slog.Attr) error) InlineOption {
+	return func(o *inlineOptions) {
+		o.handle = fn
+		o.handlerOptions = append(o.handlerOptions, "OnHandle")
+	}
+}
+
+// OnHandleResolved sets the `Handle` hook of an inline handler, like OnHandle,
+// with the record and its attributes wrapped in a ResolvedRecord, offering
+// lookups by path, iteration and conversion to maps. It replaces OnHandle.
+func OnHandleResolved(fn func(ctx context.Context, record *ResolvedRecord) error) InlineOption {
+	return func(o *inlineOptions) {
+		o.handle = func(ctx context.Context, record slog.Record, attrs []slog.Attr) error {
+			return fn(ctx, &ResolvedRecord{record: record, attrs: attrs})
+		}
+		o.handlerOptions = append(o.handlerOptions, "OnHandleResolved")
+	}
+}
+
+// OnEnabledNext sets the `Enabled` hook of an inline middleware.
+// Defaults to calling next.
+func OnEnabledNext(fn func(ctx context.Context, level slog.Level, next func(context.Context, slog.Level) bool) bool) InlineOption {
+	return func(o *inlineOptions) {
+		o.enabledNext = fn
+		o.middlewareOptions = append(o.middlewareOptions, "OnEnabledNext")
+	}
+}
+
+// OnHandleNext sets the `Handle` hook of an inline middleware.
+// Defaults to calling next.
+func OnHandleNext(fn func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error) InlineOption {
+	return func(o *inlineOptions) {
+		o.handleNext = fn
+		o.middlewareOptions = append(o.middlewareOptions, "OnHandleNext")
+	}
+}
+
+// OnWithAttrsNext sets the `WithAttrs` hook of an inline middleware.
+// Defaults to calling next.
+func OnWithAttrsNext(fn func(attrs []slog.Attr, next func([]slog.Attr) slog.Handler) slog.Handler) InlineOption {
+	return func(o *inlineOptions) {
+		o.withAttrsNext = fn
+		o.middlewareOptions = append(o.middlewareOptions, "OnWithAttrsNext")
+	}
+}
+
+// OnWithGroupNext sets the `WithGroup` hook of an inline middleware.
+// Defaults to calling next.
+func OnWithGroupNext(fn func(name string, next func(string) slog.Handler) slog.Handler) InlineOption {
+	return func(o *inlineOptions) {
+		o.withGroupNext = fn
+		o.middlewareOptions = append(o.middlewareOptions, "OnWithGroupNext")
+	}
+}
+
+// NewInlineMiddlewareWithOptions is a shortcut to a middleware in which every
+// hook is optional. Missing hooks forward the call to the next handler.
+//
+// Example usage:
+//
+//	mdw := slogmulti.NewInlineMiddlewareWithOptions(
+//	    slogmulti.WithMinLevel(slog.LevelInfo),
+//	    slogmulti.OnHandleNext(func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+//	        record.AddAttrs(slog.String("env", "prod"))
+//	        return next(ctx, record)
+//	    }),
+//	)
+//
+// Args:
+//
+//	opts: WithMinLevel and the OnXxxNext hooks
+//
+// Returns:
+//
+//	A middleware, backed by an InlineMiddleware
+//
+// It panics when a handler option (OnEnabled, OnHandle) is passed.
+func NewInlineMiddlewareWithOptions(opts ...InlineOption) Middleware {
+	options := newInlineOptions(opts)
+	if len(options.handlerOptions) > 0 {
+		panic(fmt.Sprintf("slog-multi: %s is not a middleware option", options.handlerOptions[0]))
+	}
+
+	enabledFunc := options.enabledNext
+	if enabledFunc == nil {
+		enabledFunc = func(ctx context.Context, level slog.Level, next func(context.Context, slog.Level) bool) bool {
+			return next(ctx, level)
+		}
+	}
+
+	handleFunc := options.handleNext
+	if handleFunc == nil {
+		handleFunc = func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+			return next(ctx, record)
+		}
+	}
+
+	withAttrsFunc := options.withAttrsNext
+	if withAttrsFunc == nil {
+		withAttrsFunc = func(attrs []slog.Attr, next func([]slog.Attr) slog.Handler) slog.Handler {
+			return next(attrs)
+		}
+	}
+
+	withGroupFunc := options.withGroupNext
+	if withGroupFunc == nil {
+		withGroupFunc = func(name string, next func(string) slog.Handler) slog.Handler {
+			return next(name)
+		}
+	}
+
+	if options.minLevel != nil {
+		enabled, handle := enabledFunc, handleFunc
+		enabledFunc = func(ctx context.Context, level slog.Level, next func(context.Context, slog.Level) bool) bool {
+			return options.enabledLevel(level) && enabled(ctx, level, next)
+		}
+		handleFunc = func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+			if !options.enabledLevel(record.Level) {
+				return nil
+			}
+			return handle(ctx, record, next)
+		}
+	}
+
+	return NewInlineMiddleware(enabledFunc, handleFunc, withAttrsFunc, withGroupFunc)
+}
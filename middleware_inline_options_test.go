@@ -0,0 +1,67 @@
+package slogmulti
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineMiddlewareWithOptions(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	buf := bytes.NewBufferString("")
+	sink := slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: remoteTimeReplaceAttr})
+
+	// pass-through by default
+	logger := slog.New(Pipe(NewInlineMiddlewareWithOptions()).Handler(sink))
+	logger.With("a", 1).WithGroup("g").Debug("hello", "b", 2)
+	is.Equal("level=DEBUG msg=hello a=1 g.b=2\n", buf.String())
+
+	buf.Reset()
+	logger = slog.New(Pipe(NewInlineMiddlewareWithOptions(
+		WithMinLevel(slog.LevelInfo),
+		OnHandleNext(func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+			record.AddAttrs(slog.String("env", "prod"))
+			return next(ctx, record)
+		}),
+		OnWithGroupNext(func(name string, next func(string) slog.Handler) slog.Handler {
+			return next("prefix_" + name)
+		}),
+	)).Handler(sink))
+
+	is.False(logger.Enabled(context.Background(), slog.LevelDebug))
+	logger.Debug("dropped")
+	logger.WithGroup("g").Info("hello", "b", 2)
+	is.Equal("level=INFO msg=hello prefix_g.b=2 prefix_g.env=prod\n", buf.String())
+}
+
+func TestInlineMiddlewareWithOptionsEnabled(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	sink := &countingHandler{}
+	handler := Pipe(NewInlineMiddlewareWithOptions(
+		WithMinLevel(slog.LevelInfo),
+		OnEnabledNext(func(ctx context.Context, level slog.Level, next func(context.Context, slog.Level) bool) bool {
+			return level != slog.LevelWarn && next(ctx, level)
+		}),
+		OnWithAttrsNext(func(attrs []slog.Attr, next func([]slog.Attr) slog.Handler) slog.Handler {
+			return next(append(attrs, slog.Bool("added", true)))
+		}),
+	)).Handler(sink)
+
+	is.False(handler.Enabled(context.Background(), slog.LevelDebug))
+	is.True(handler.Enabled(context.Background(), slog.LevelInfo))
+	is.False(handler.Enabled(context.Background(), slog.LevelWarn))
+	is.IsType(&InlineMiddleware{}, handler.WithAttrs([]slog.Attr{slog.Int("a", 1)}))
+
+	is.PanicsWithValue("slog-multi: OnHandle is not a middleware option", func() {
+		NewInlineMiddlewareWithOptions(OnHandle(func(ctx context.Context, record slog.Record, attrs []slog.Attr) error {
+			return nil
+		}))
+	})
+}
@@ -54,3 +54,18 @@ func (h *WithAttrsInlineMiddleware) WithGroup(name string) slog.Handler {
 
 	return NewWithAttrsInlineMiddleware(h.withAttrsFunc)(h.next.WithGroup(name))
 }
+
+// Implements slogmulti.Flusher
+func (h *WithAttrsInlineMiddleware) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Closer
+func (h *WithAttrsInlineMiddleware) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Inspector
+func (h *WithAttrsInlineMiddleware) Inspect() HandlerNode {
+	return inspectNode("WithAttrsInlineMiddleware", nil, nil, h.next)
+}
@@ -54,3 +54,18 @@ func (h *WithGroupInlineMiddleware) WithGroup(name string) slog.Handler {
 
 	return NewWithGroupInlineMiddleware(h.withGroupFunc)(h.withGroupFunc(name, h.next.WithGroup))
 }
+
+// Implements slogmulti.Flusher
+func (h *WithGroupInlineMiddleware) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Closer
+func (h *WithGroupInlineMiddleware) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Implements slogmulti.Inspector
+func (h *WithGroupInlineMiddleware) Inspect() HandlerNode {
+	return inspectNode("WithGroupInlineMiddleware", nil, nil, h.next)
+}
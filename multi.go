@@ -168,3 +168,37 @@ func (h *FanoutHandler) WithGroup(name string) slog.Handler {
 	})
 	return Fanout(handlers...)
 }
+
+// Flush flushes all child handlers implementing Flusher.
+// This method implements the Flusher interface.
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//
+// Returns:
+//
+//	The joined errors of the child handlers
+func (h *FanoutHandler) Flush(ctx context.Context) error {
+	return flushHandlers(ctx, h.handlers...)
+}
+
+// Close closes all child handlers implementing Closer.
+// This method implements the Closer interface.
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//
+// Returns:
+//
+//	The joined errors of the child handlers
+func (h *FanoutHandler) Close(ctx context.Context) error {
+	return closeHandlers(ctx, h.handlers...)
+}
+
+// Inspect describes the Fanout handler and its children.
+// This method implements the Inspector interface.
+func (h *FanoutHandler) Inspect() HandlerNode {
+	return inspectNode("Fanout", nil, nil, h.handlers...)
+}
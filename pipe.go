@@ -2,11 +2,15 @@ package slogmulti
 
 import (
 	"log/slog"
+	"slices"
 )
 
 // PipeBuilder provides a fluent API for building middleware chains.
 // It allows you to compose multiple middleware functions that will be applied
 // to log records in the order they are added (last-in, first-out).
+//
+// A PipeBuilder is immutable: Pipe returns a new builder, and Handler can be
+// called any number of times. Builders sharing a prefix can be forked safely.
 type PipeBuilder struct {
 	// middlewares contains the list of middleware functions to be applied
 	// The middlewares are applied in reverse order (LIFO) when building the final handler
@@ -37,22 +41,60 @@ type PipeBuilder struct {
 //
 //	A new PipeBuilder instance ready for further configuration
 func Pipe(middlewares ...Middleware) *PipeBuilder {
+	return &PipeBuilder{middlewares: slices.Clone(middlewares)}
+}
+
+// Compose concatenates middleware chains into a new PipeBuilder. The middlewares
+// of the first pipe are applied first to incoming records.
+//
+// Example usage:
+//
+//	common := slogmulti.Pipe(slogmulti.ContextAttrs(""), slogmulti.TraceAttrs())
+//	privacy := slogmulti.Pipe(RedactPII())
+//
+//	handler := slogmulti.Compose(common, privacy).Handler(finalHandler)
+//
+// Args:
+//
+//	pipes: The middleware chains to concatenate, nil pipes are ignored
+//
+// Returns:
+//
+//	A new PipeBuilder instance
+func Compose(pipes ...*PipeBuilder) *PipeBuilder {
+	middlewares := []Middleware{}
+	for _, pipe := range pipes {
+		if pipe != nil {
+			middlewares = append(middlewares, pipe.middlewares...)
+		}
+	}
+
 	return &PipeBuilder{middlewares: middlewares}
 }
 
-// Pipe adds an additional middleware to the chain.
-// This method provides a fluent API for building middleware chains incrementally.
+// Pipe returns a new PipeBuilder with additional middlewares appended to the chain.
+// The receiver is left unchanged, so that a common prefix can be forked.
 //
 // Args:
 //
-//	middleware: The middleware function to add to the chain
+//	middlewares: The middleware functions to add to the chain
 //
 // Returns:
 //
-//	The PipeBuilder instance for method chaining
-func (h *PipeBuilder) Pipe(middleware Middleware) *PipeBuilder {
-	h.middlewares = append(h.middlewares, middleware)
-	return h
+//	A new PipeBuilder instance for method chaining
+func (h *PipeBuilder) Pipe(middlewares ...Middleware) *PipeBuilder {
+	output := make([]Middleware, 0, len(h.middlewares)+len(middlewares))
+	output = append(output, h.middlewares...)
+	output = append(output, middlewares...)
+
+	return &PipeBuilder{middlewares: output}
+}
+
+// Middleware returns the chain as a single Middleware.
+func (h *PipeBuilder) Middleware() Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return h.Handler(next)
+	}
 }
 
 // Handler creates a slog.Handler by applying all middleware to the provided handler.
@@ -61,6 +103,8 @@ func (h *PipeBuilder) Pipe(middleware Middleware) *PipeBuilder {
 // This LIFO approach ensures that the middleware chain is applied in the intuitive order:
 // the first middleware in the chain is applied first to incoming records.
 //
+// The builder is left unchanged, so Handler can be called any number of times.
+//
 // Args:
 //
 //	handler: The final slog.Handler that will receive the transformed records
@@ -69,10 +113,8 @@ func (h *PipeBuilder) Pipe(middleware Middleware) *PipeBuilder {
 //
 //	A slog.Handler that applies all middleware transformations before forwarding to the final handler
 func (h *PipeBuilder) Handler(handler slog.Handler) slog.Handler {
-	for len(h.middlewares) > 0 {
-		middleware := h.middlewares[len(h.middlewares)-1]
-		h.middlewares = h.middlewares[0 : len(h.middlewares)-1]
-		handler = middleware(handler)
+	for i := len(h.middlewares) - 1; i >= 0; i-- {
+		handler = h.middlewares[i](handler)
 	}
 
 	return handler
@@ -0,0 +1,87 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/samber/slog-multi/slogmultitest"
+	"github.com/stretchr/testify/assert"
+)
+
+// tagMiddleware appends its name to the "path" attribute of each record.
+func tagMiddleware(name string) Middleware {
+	return NewHandleInlineMiddleware(func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+		path := name
+		record.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == "path" {
+				path = attr.Value.String() + ">" + name
+			}
+			return true
+		})
+
+		output := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+		output.AddAttrs(slog.String("path", path))
+		return next(ctx, output)
+	})
+}
+
+func runPipe(t *testing.T, pipe *PipeBuilder) string {
+	t.Helper()
+
+	recorder := slogmultitest.NewRecordingHandler(nil)
+	err := pipe.Handler(recorder).Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, ok := recorder.Last()
+	if !ok {
+		t.Fatal("no record")
+	}
+
+	value, _ := record.AttrAt("path")
+	return value.String()
+}
+
+func TestPipeReusable(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	pipe := Pipe(tagMiddleware("a"), tagMiddleware("b"))
+
+	is.Equal("a>b", runPipe(t, pipe))
+	is.Equal("a>b", runPipe(t, pipe))
+}
+
+func TestPipeFork(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	middlewares := []Middleware{tagMiddleware("a"), tagMiddleware("b")}
+	prefix := Pipe(middlewares...)
+	middlewares[0] = tagMiddleware("x")
+
+	left := prefix.Pipe(tagMiddleware("left"))
+	right := prefix.Pipe(tagMiddleware("right"), tagMiddleware("end"))
+
+	is.Equal("a>b", runPipe(t, prefix))
+	is.Equal("a>b>left", runPipe(t, left))
+	is.Equal("a>b>right>end", runPipe(t, right))
+}
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	first := Pipe(tagMiddleware("a"))
+	second := Pipe(tagMiddleware("b"), tagMiddleware("c"))
+
+	is.Equal("a>b>c", runPipe(t, Compose(first, nil, second)))
+	is.Equal("b>c>a", runPipe(t, Compose(second, first)))
+	is.Equal("a>b>c>a", runPipe(t, Pipe(first.Middleware(), second.Middleware()).Pipe(first.Middleware())))
+
+	recorder := slogmultitest.NewRecordingHandler(nil)
+	is.Same(recorder, Compose().Handler(recorder))
+}
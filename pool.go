@@ -161,3 +161,37 @@ func (h *PoolHandler) WithGroup(name string) slog.Handler {
 	})
 	return Pool()(handers...)
 }
+
+// Flush flushes all child handlers implementing Flusher.
+// This method implements the Flusher interface.
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//
+// Returns:
+//
+//	The joined errors of the child handlers
+func (h *PoolHandler) Flush(ctx context.Context) error {
+	return flushHandlers(ctx, h.handlers...)
+}
+
+// Close closes all child handlers implementing Closer.
+// This method implements the Closer interface.
+//
+// Args:
+//
+//	ctx: The context carrying the deadline of the operation
+//
+// Returns:
+//
+//	The joined errors of the child handlers
+func (h *PoolHandler) Close(ctx context.Context) error {
+	return closeHandlers(ctx, h.handlers...)
+}
+
+// Inspect describes the Pool handler and its children.
+// This method implements the Inspector interface.
+func (h *PoolHandler) Inspect() HandlerNode {
+	return inspectNode("Pool", nil, nil, h.handlers...)
+}
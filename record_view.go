@@ -0,0 +1,114 @@
+package slogmulti
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	slogcommon "github.com/samber/slog-common"
+	"github.com/samber/slog-multi/internal/slogattr"
+)
+
+// RecordView is a read-only view of a record, shared by every route of a router.
+// It merges the attributes accumulated with WithAttrs and WithGroup with the
+// record attributes. The merged attributes, the attribute index and the merged
+// record are built lazily, at most once per record, whatever the number of routes.
+//
+// A RecordView is only valid during the evaluation of the record and must not be
+// retained nor modified.
+type RecordView struct {
+	record slog.Record
+	groups []string
+	attrs  []slog.Attr
+
+	merged []slog.Attr
+	clone  *slog.Record
+	index  map[string]slog.Value
+}
+
+// NewRecordView returns a view of a record, without accumulated attributes.
+// It is mostly useful to test matchers.
+func NewRecordView(record slog.Record) *RecordView {
+	return newRecordView(record, nil, nil)
+}
+
+func newRecordView(record slog.Record, groups []string, attrs []slog.Attr) *RecordView {
+	return &RecordView{
+		record: record,
+		groups: groups,
+		attrs:  attrs,
+	}
+}
+
+// Record returns the original record, without the accumulated attributes.
+func (v *RecordView) Record() slog.Record {
+	return v.record
+}
+
+// Time returns the time of the record.
+func (v *RecordView) Time() time.Time {
+	return v.record.Time
+}
+
+// Level returns the level of the record.
+func (v *RecordView) Level() slog.Level {
+	return v.record.Level
+}
+
+// Message returns the message of the record.
+func (v *RecordView) Message() string {
+	return v.record.Message
+}
+
+// Attrs returns the accumulated attributes followed by the record attributes,
+// nested under their groups. Attributes sharing the same key are all kept, so
+// that predicates see every value. The returned slice is shared and must not be modified.
+func (v *RecordView) Attrs() []slog.Attr {
+	if v.merged == nil {
+		v.merged = slogcommon.AppendRecordAttrsToAttrs(v.attrs, v.groups, &v.record)
+	}
+
+	return v.merged
+}
+
+// Value returns the value of the last top-level attribute with the given key.
+// Dotted keys ("user.id") are looked up in groups when no top-level attribute
+// has this exact key.
+func (v *RecordView) Value(key string) (slog.Value, bool) {
+	if v.index == nil {
+		attrs := v.Attrs()
+		v.index = make(map[string]slog.Value, len(attrs))
+		for _, attr := range attrs {
+			v.index[attr.Key] = attr.Value
+		}
+	}
+
+	if value, ok := v.index[key]; ok {
+		return value, true
+	}
+
+	if strings.Contains(key, ".") {
+		if attr, ok := slogattr.FindByPath(v.Attrs(), slogattr.SplitPath(key)); ok {
+			return attr.Value, true
+		}
+	}
+
+	return slog.Value{}, false
+}
+
+// MergedRecord returns a record carrying the merged attributes, as seen by
+// `func(ctx, slog.Record) bool` predicates. It is built once and shared. Without
+// accumulated attributes nor groups, the original record is returned.
+func (v *RecordView) MergedRecord() slog.Record {
+	if len(v.attrs) == 0 && len(v.groups) == 0 {
+		return v.record
+	}
+
+	if v.clone == nil {
+		clone := slog.NewRecord(v.record.Time, v.record.Level, v.record.Message, v.record.PC)
+		clone.AddAttrs(v.Attrs()...)
+		v.clone = &clone
+	}
+
+	return *v.clone
+}
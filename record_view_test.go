@@ -0,0 +1,53 @@
+package slogmulti
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordView(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	record := slog.NewRecord(time.Now(), slog.LevelWarn, "hello", 0)
+	record.AddAttrs(slog.String("foo", "bar"), slog.Group("user", slog.Int("id", 42)))
+
+	view := newRecordView(record, []string{"http"}, []slog.Attr{slog.String("env", "prod")})
+	is.Equal(slog.LevelWarn, view.Level())
+	is.Equal("hello", view.Message())
+	is.Equal(record.Time, view.Time())
+	is.Equal(2, view.Record().NumAttrs())
+
+	// like slogcommon.AppendRecordAttrsToAttrs, each record attribute is nested on its own
+	is.Len(view.Attrs(), 3)
+	is.Equal("env", view.Attrs()[0].Key)
+	is.Equal("http", view.Attrs()[1].Key)
+	is.Equal("http", view.Attrs()[2].Key)
+
+	value, ok := view.Value("env")
+	is.True(ok)
+	is.Equal("prod", value.String())
+	value, ok = view.Value("http.foo")
+	is.True(ok)
+	is.Equal("bar", value.String())
+	value, ok = view.Value("http.user.id")
+	is.True(ok)
+	is.EqualValues(42, value.Int64())
+	_, ok = view.Value("foo")
+	is.False(ok)
+
+	merged := view.MergedRecord()
+	is.Equal(3, merged.NumAttrs())
+	is.Equal(slog.LevelWarn, merged.Level)
+
+	// the view is built once
+	is.Equal(&view.Attrs()[0], &view.Attrs()[0])
+
+	view = NewRecordView(record)
+	value, ok = view.Value("foo")
+	is.True(ok)
+	is.Equal("bar", value.String())
+}
@@ -149,3 +149,21 @@ func (h *HandlerErrorRecovery) WithGroup(name string) slog.Handler {
 		handler:  h.handler.WithGroup(name),
 	}
 }
+
+// Flush flushes the underlying handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *HandlerErrorRecovery) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.handler)
+}
+
+// Close closes the underlying handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *HandlerErrorRecovery) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.handler)
+}
+
+// Inspect describes the recovery middleware and the underlying handler.
+// This method implements the Inspector interface.
+func (h *HandlerErrorRecovery) Inspect() HandlerNode {
+	return inspectNode("Recover", nil, nil, h.handler)
+}
@@ -0,0 +1,128 @@
+package slogmulti
+
+import (
+	"log/slog"
+	"time"
+
+	slogcommon "github.com/samber/slog-common"
+	"github.com/samber/slog-multi/internal/slogattr"
+)
+
+// ResolvedRecord is a record whose attributes are fully resolved: attributes
+// added with WithAttrs are nested under the groups opened before them, record
+// attributes are nested under the current groups, slog.LogValuer values are
+// resolved, empty attributes and empty groups are removed and groups without
+// key are inlined.
+//
+// It saves InlineHandler and HandleInlineHandler users from reimplementing
+// the slog.Handler rules for groups and attributes. Handlers built with
+// NewInlineHandlerWithOptions receive it with OnHandleResolved.
+type ResolvedRecord struct {
+	record slog.Record
+	attrs  []slog.Attr
+}
+
+// ResolveRecord builds a ResolvedRecord from the arguments of the callbacks of
+// NewInlineHandler and NewHandleInlineHandler.
+//
+// Example usage:
+//
+//	handler := slogmulti.NewHandleInlineHandler(
+//	    func(ctx context.Context, groups []string, attrs []slog.Attr, record slog.Record) error {
+//	        resolved := slogmulti.ResolveRecord(groups, attrs, record)
+//	        status, _ := resolved.Lookup("http.status")
+//	        fmt.Println(record.Message, status, resolved.Map())
+//	        return nil
+//	    },
+//	)
+//
+// Args:
+//
+//	groups: the groups received by the callback
+//	attrs: the attributes received by the callback, where inline handlers nest
+//	    the attributes added after WithGroup under their groups
+//	record: the record received by the callback
+//
+// Returns:
+//
+//	The resolved record
+func ResolveRecord(groups []string, attrs []slog.Attr, record slog.Record) *ResolvedRecord {
+	return &ResolvedRecord{
+		record: record,
+		attrs:  slogattr.Resolve(mergeRecordAttrs(attrs, groups, &record)),
+	}
+}
+
+// Time returns the time of the record.
+func (r *ResolvedRecord) Time() time.Time {
+	return r.record.Time
+}
+
+// Level returns the level of the record.
+func (r *ResolvedRecord) Level() slog.Level {
+	return r.record.Level
+}
+
+// Message returns the message of the record.
+func (r *ResolvedRecord) Message() string {
+	return r.record.Message
+}
+
+// PC returns the program counter of the record.
+func (r *ResolvedRecord) PC() uintptr {
+	return r.record.PC
+}
+
+// Attrs returns the resolved attribute tree. The returned slice must not be modified.
+func (r *ResolvedRecord) Attrs() []slog.Attr {
+	return r.attrs
+}
+
+// NumAttrs returns the number of top-level attributes.
+func (r *ResolvedRecord) NumAttrs() int {
+	return len(r.attrs)
+}
+
+// Walk calls fn on every leaf attribute, depth first, with its dotted path
+// ("http.status"). Iteration stops when fn returns false.
+func (r *ResolvedRecord) Walk(fn func(path string, value slog.Value) bool) {
+	walkAttrs("", r.attrs, fn)
+}
+
+func walkAttrs(prefix string, attrs []slog.Attr, fn func(path string, value slog.Value) bool) bool {
+	for _, attr := range attrs {
+		if attr.Value.Kind() == slog.KindGroup {
+			if !walkAttrs(prefix+attr.Key+".", attr.Value.Group(), fn) {
+				return false
+			}
+			continue
+		}
+
+		if !fn(prefix+attr.Key, attr.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Lookup returns the value of the last attribute located at the given dotted
+// path ("http.status"). Groups can be looked up too.
+func (r *ResolvedRecord) Lookup(path string) (slog.Value, bool) {
+	attr, ok := slogattr.FindByPath(r.attrs, slogattr.SplitPath(path))
+	if !ok {
+		return slog.Value{}, false
+	}
+
+	return attr.Value, true
+}
+
+// Map converts the attribute tree to nested maps: groups are map[string]any.
+func (r *ResolvedRecord) Map() map[string]any {
+	return slogcommon.AttrsToMap(r.attrs...)
+}
+
+// Record returns a copy of the record carrying the resolved attributes.
+func (r *ResolvedRecord) Record() slog.Record {
+	return recordWithAttrs(r.record, r.attrs)
+}
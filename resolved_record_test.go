@@ -0,0 +1,110 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRecord(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var resolved *ResolvedRecord
+	handler := NewHandleInlineHandler(func(ctx context.Context, groups []string, attrs []slog.Attr, record slog.Record) error {
+		resolved = ResolveRecord(groups, attrs, record)
+		return nil
+	})
+
+	logger := slog.New(handler).
+		With("env", "prod").
+		WithGroup("http").
+		With("method", "GET").
+		WithGroup("response")
+	logger.Info("hello", "status", 200, "user", inlineOptionsUser{id: "42"}, slog.Group("empty"))
+
+	is.Equal("hello", resolved.Message())
+	is.Equal(slog.LevelInfo, resolved.Level())
+	is.Equal(2, resolved.NumAttrs())
+	is.Equal(
+		map[string]any{
+			"env": "prod",
+			"http": map[string]any{
+				"method": "GET",
+				"response": map[string]any{
+					"status": int64(200),
+					"user":   map[string]any{"id": "42"},
+				},
+			},
+		},
+		resolved.Map(),
+	)
+
+	value, ok := resolved.Lookup("http.response.user.id")
+	is.True(ok)
+	is.Equal("42", value.String())
+	value, ok = resolved.Lookup("http.method")
+	is.True(ok)
+	is.Equal("GET", value.String())
+	_, ok = resolved.Lookup("http.response.empty")
+	is.False(ok)
+
+	paths := []string{}
+	resolved.Walk(func(path string, value slog.Value) bool {
+		paths = append(paths, path)
+		return true
+	})
+	is.Equal([]string{"env", "http.method", "http.response.status", "http.response.user.id"}, paths)
+
+	paths = []string{}
+	resolved.Walk(func(path string, value slog.Value) bool {
+		paths = append(paths, path)
+		return len(paths) < 2
+	})
+	is.Equal([]string{"env", "http.method"}, paths)
+
+	record := resolved.Record()
+	is.Equal(2, record.NumAttrs())
+}
+
+func TestResolveRecordInlineHandler(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var resolved *ResolvedRecord
+	var flat []slog.Attr
+	handler := NewInlineHandler(
+		func(ctx context.Context, groups []string, attrs []slog.Attr, level slog.Level) bool { return true },
+		func(ctx context.Context, groups []string, attrs []slog.Attr, record slog.Record) error {
+			flat = attrs
+			resolved = ResolveRecord(groups, attrs, record)
+			return nil
+		},
+	)
+
+	logger := slog.New(handler).With("env", "prod").WithGroup("g")
+	logger.Info("hello", "a", 1)
+	is.Equal(map[string]any{"env": "prod", "g": map[string]any{"a": int64(1)}}, resolved.Map())
+
+	// attributes added after WithGroup are nested under their groups
+	logger.With("b", 2).With("c", 3).WithGroup("h").With("d", 4).Info("hello", "a", 1)
+	is.Equal(map[string]any{
+		"env": "prod",
+		"g": map[string]any{
+			"b": int64(2),
+			"c": int64(3),
+			"h": map[string]any{"d": int64(4), "a": int64(1)},
+		},
+	}, resolved.Map())
+
+	// attributes added before any group are passed as is, duplicates included
+	slog.New(handler).With("env", "prod").With("env", "dev").Info("hello")
+	is.Equal([]slog.Attr{slog.String("env", "prod"), slog.String("env", "dev")}, flat)
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+	resolved = ResolveRecord(nil, []slog.Attr{slog.Any("user", inlineOptionsUser{id: "42"}), slog.Group("empty")}, record)
+	is.Equal(map[string]any{"user": map[string]any{"id": "42"}}, resolved.Map())
+}
@@ -0,0 +1,114 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	slogcommon "github.com/samber/slog-common"
+)
+
+// rootAttrs adds attributes at the top level of records, outside of the groups
+// opened with WithGroup. It is shared by middlewares adding metadata to records
+// (see TraceAttrs and Enrich).
+//
+// Groups and attributes are forwarded to the next handler as usual, but the
+// handler received before the first WithGroup is kept: records receiving
+// top-level attributes after a group was opened are rebuilt, with the attributes
+// added since, and sent to that handler. Other records are forwarded unchanged.
+type rootAttrs struct {
+	// root is the next handler, before the first WithGroup
+	root slog.Handler
+	// keys contains the keys of the top-level attributes added with WithAttrs
+	keys []string
+	// groups tracks the groups opened after root
+	groups []string
+	// attrs contains the attributes added after the first group, nested under their groups
+	attrs []slog.Attr
+}
+
+func newRootAttrs(next slog.Handler) rootAttrs {
+	return rootAttrs{
+		root:   next,
+		keys:   []string{},
+		groups: []string{},
+		attrs:  []slog.Attr{},
+	}
+}
+
+// withAttrs returns the state of a handler whose next handler is next.WithAttrs(attrs).
+func (a rootAttrs) withAttrs(next slog.Handler, attrs []slog.Attr) rootAttrs {
+	if len(a.groups) == 0 {
+		keys := slices.Clone(a.keys)
+		for _, attr := range attrs {
+			keys = append(keys, attr.Key)
+		}
+
+		return rootAttrs{
+			root:   next,
+			keys:   keys,
+			groups: a.groups,
+			attrs:  a.attrs,
+		}
+	}
+
+	return rootAttrs{
+		root:   a.root,
+		keys:   a.keys,
+		groups: a.groups,
+		attrs:  slogcommon.AppendAttrsToGroup(a.groups, a.attrs, attrs...),
+	}
+}
+
+// withGroup returns the state of a handler whose next handler is next.WithGroup(name).
+func (a rootAttrs) withGroup(name string) rootAttrs {
+	return rootAttrs{
+		root:   a.root,
+		keys:   a.keys,
+		groups: append(slices.Clone(a.groups), name),
+		attrs:  a.attrs,
+	}
+}
+
+// handle adds attrs at the top level of the record, except the ones whose key
+// is already present at the top level, and forwards it.
+func (a rootAttrs) handle(ctx context.Context, next slog.Handler, r slog.Record, attrs []slog.Attr) error {
+	missing := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		if !a.hasKey(r, attr.Key) {
+			missing = append(missing, attr)
+		}
+	}
+
+	if len(missing) == 0 {
+		return next.Handle(ctx, r)
+	}
+
+	if len(a.groups) == 0 {
+		r = r.Clone()
+		r.AddAttrs(missing...)
+		return next.Handle(ctx, r)
+	}
+
+	return a.root.Handle(ctx, recordWithAttrs(r, slices.Concat(mergeRecordAttrs(a.attrs, a.groups, &r), missing)))
+}
+
+// hasKey checks if a top-level attribute has the given key. After WithGroup,
+// record attributes are nested, so only the attributes added before are checked.
+func (a rootAttrs) hasKey(r slog.Record, key string) bool {
+	if slices.Contains(a.keys, key) {
+		return true
+	}
+
+	if len(a.groups) > 0 {
+		return false
+	}
+
+	found := false
+	r.Attrs(func(attr slog.Attr) bool {
+		found = attr.Key == key
+		return !found
+	})
+
+	return found
+}
@@ -1,10 +1,12 @@
 package slogmulti
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
 	"slices"
+	"sync/atomic"
 
 	"github.com/samber/lo"
 	slogcommon "github.com/samber/slog-common"
@@ -13,6 +15,8 @@ import (
 type router struct {
 	handlers   []slog.Handler
 	firstMatch bool
+	fallback   slog.Handler
+	unrouted   *atomic.Int64
 }
 
 // Router creates a new router instance for building conditional log routing.
@@ -47,18 +51,231 @@ func Router() *router {
 //
 //	The router instance for method chaining
 func (h *router) Add(handler slog.Handler, predicates ...func(ctx context.Context, r slog.Record) bool) *router {
+	return h.AddNamed("", handler, predicates...)
+}
+
+// AddNamed registers a new named handler with optional predicates to the router.
+// The name is a human-readable label, reported by Explain and Inspect.
+//
+// Example usage:
+//
+//	r := slogmulti.Router().
+//	    AddNamed("errors-to-slack", slackHandler, slogmulti.LevelIs(slog.LevelError)).
+//	    AddNamed("everything-to-stdout", stdoutHandler)
+//
+// Args:
+//
+//	name: The name of the route
+//	handler: The slog.Handler to register
+//	predicates: Optional functions that determine if a record should be routed to this handler
+//
+// Returns:
+//
+//	The router instance for method chaining
+func (h *router) AddNamed(name string, handler slog.Handler, predicates ...func(ctx context.Context, r slog.Record) bool) *router {
+	return h.AddRoute(Route{
+		Name:       name,
+		Handler:    handler,
+		Predicates: predicates,
+	})
+}
+
+// AddMatchers registers a new handler with optional matchers to the router.
+// The handler will only process records if all provided matchers match.
+//
+// Matchers read a RecordView shared by every route, so that the attributes of a
+// record are merged once, whatever the number of routes.
+//
+// Example usage:
+//
+//	r := slogmulti.Router().
+//	    AddMatchers(acmeHandler, slogmulti.MatchAttr("tenant", "acme")).
+//	    AddMatchers(slackHandler, slogmulti.MatchLevel(slog.LevelError)).
+//	    Handler()
+//
+// Args:
+//
+//	handler: The slog.Handler to register
+//	matchers: Optional matchers that determine if a record should be routed to this handler
+//
+// Returns:
+//
+//	The router instance for method chaining
+func (h *router) AddMatchers(handler slog.Handler, matchers ...Matcher) *router {
+	return h.AddRoute(Route{
+		Handler:  handler,
+		Matchers: matchers,
+	})
+}
+
+// Route describes a route of a router, for AddRoute.
+type Route struct {
+	// Name is an optional human-readable label, reported by Explain and Inspect
+	Name string
+	// Handler receives the matching records
+	Handler slog.Handler
+	// Predicates must all return true for a record to match the route
+	Predicates []func(ctx context.Context, r slog.Record) bool
+	// Matchers must all match for a record to match the route. They are evaluated
+	// after Predicates and share the RecordView of the record with other routes.
+	Matchers []Matcher
+	// Priority orders routes: higher priorities are evaluated first.
+	// Routes with the same priority are evaluated in insertion order.
+	Priority int
+	// Continue lets the evaluation go on after this route matched, in FirstMatch mode.
+	// Without FirstMatch, every matching route receives the record.
+	Continue bool
+}
+
+// AddRoute registers a new route to the router, with a name, a priority and
+// "continue" semantics.
+//
+// Example usage:
+//
+//	r := slogmulti.Router().
+//	    AddRoute(slogmulti.Route{
+//	        Name:       "audit",
+//	        Handler:    auditHandler,
+//	        Predicates: []func(context.Context, slog.Record) bool{slogmulti.AttrValueIs("scope", "audit")},
+//	        Continue:   true, // audit records are sent to the next matching route as well
+//	    }).
+//	    AddRoute(slogmulti.Route{Name: "errors", Handler: slackHandler, Predicates: ..., Priority: 10}).
+//	    Add(consoleHandler).
+//	    FirstMatch()
+//
+// Args:
+//
+//	route: The route to register
+//
+// Returns:
+//
+//	The router instance for method chaining
+func (h *router) AddRoute(route Route) *router {
 	return &router{
 		handlers: append(
-			h.handlers,
+			slices.Clone(h.handlers),
 			&RoutableHandler{
+				name:           route.Name,
+				predicates:     route.Predicates,
+				matchers:       route.Matchers,
+				handler:        route.Handler,
+				groups:         []string{},
+				attrs:          []slog.Attr{},
+				skipPredicates: false,
+				priority:       route.Priority,
+				continues:      route.Continue,
+			},
+		),
+		firstMatch: h.firstMatch,
+		fallback:   h.fallback,
+		unrouted:   h.unrouted,
+	}
+}
+
+// Drop registers a terminal route discarding matching records (eg: health-check noise).
+// The evaluation stops at a drop route, so later routes and the default route do not
+// receive the record. Use DropWithPriority to evaluate it before routes added earlier.
+//
+// Example usage:
+//
+//	r := slogmulti.Router().
+//	    Drop(slogmulti.MessageIs("GET /healthz")).
+//	    Add(consoleHandler).
+//	    Handler()
+//
+// Args:
+//
+//	predicates: Functions that determine if a record should be dropped
+//
+// Returns:
+//
+//	The router instance for method chaining
+func (h *router) Drop(predicates ...func(ctx context.Context, r slog.Record) bool) *router {
+	return h.DropWithPriority(0, predicates...)
+}
+
+// DropWithPriority registers a terminal route discarding matching records, with a priority.
+//
+// Args:
+//
+//	priority: The priority of the route, higher priorities are evaluated first
+//	predicates: Functions that determine if a record should be dropped
+//
+// Returns:
+//
+//	The router instance for method chaining
+func (h *router) DropWithPriority(priority int, predicates ...func(ctx context.Context, r slog.Record) bool) *router {
+	return &router{
+		handlers: append(
+			slices.Clone(h.handlers),
+			&RoutableHandler{
+				name:           "drop",
 				predicates:     predicates,
-				handler:        handler,
+				handler:        dropHandler{},
 				groups:         []string{},
 				attrs:          []slog.Attr{},
 				skipPredicates: false,
+				priority:       priority,
+				drop:           true,
 			},
 		),
 		firstMatch: h.firstMatch,
+		fallback:   h.fallback,
+		unrouted:   h.unrouted,
+	}
+}
+
+// Default registers a fallback handler, receiving every record that no route matched.
+// Without a default route, records matching no route are dropped.
+//
+// Example usage:
+//
+//	r := slogmulti.Router().
+//	    Add(slackHandler, slogmulti.LevelIs(slog.LevelError)).
+//	    Add(consoleHandler, slogmulti.LevelIs(slog.LevelInfo)).
+//	    Default(fileHandler). // receives DEBUG and WARN records
+//	    Handler()
+//
+// Args:
+//
+//	handler: The slog.Handler receiving unrouted records
+//
+// Returns:
+//
+//	The router instance for method chaining
+func (h *router) Default(handler slog.Handler) *router {
+	return &router{
+		handlers:   h.handlers,
+		firstMatch: h.firstMatch,
+		fallback:   handler,
+		unrouted:   h.unrouted,
+	}
+}
+
+// CountUnrouted increments the counter for every record that no route matched,
+// whether a default route is registered or not. It helps noticing gaps in the routing table.
+//
+// Example usage:
+//
+//	var unrouted atomic.Int64
+//	r := slogmulti.Router().
+//	    Add(slackHandler, slogmulti.LevelIs(slog.LevelError)).
+//	    CountUnrouted(&unrouted).
+//	    Handler()
+//
+// Args:
+//
+//	counter: The counter of unrouted records
+//
+// Returns:
+//
+//	The router instance for method chaining
+func (h *router) CountUnrouted(counter *atomic.Int64) *router {
+	return &router{
+		handlers:   h.handlers,
+		firstMatch: h.firstMatch,
+		fallback:   h.fallback,
+		unrouted:   counter,
 	}
 }
 
@@ -70,23 +287,37 @@ func (h *router) Add(handler slog.Handler, predicates ...func(ctx context.Contex
 //
 //	A slog.Handler that implements the routing logic
 func (h *router) Handler() slog.Handler {
-	if h.firstMatch {
-		return FirstMatch(lo.Map(h.handlers, func(h slog.Handler, _ int) *RoutableHandler {
-			rh, ok := h.(*RoutableHandler)
-			if !ok {
-				panic(fmt.Sprintf("expected *RoutableHandler, got %T", h))
-			}
-			return rh
-		})...)
-	} else {
-		return Fanout(h.handlers...)
-	}
+	return newRouterHandler(h.routes(), h.firstMatch, h.fallback, h.unrouted)
+}
+
+// routes returns the routes in evaluation order: by descending priority, then by insertion order.
+func (h *router) routes() []*RoutableHandler {
+	routes := lo.Map(h.handlers, func(h slog.Handler, _ int) *RoutableHandler {
+		rh, ok := h.(*RoutableHandler)
+		if !ok {
+			panic(fmt.Sprintf("expected *RoutableHandler, got %T", h))
+		}
+		return rh
+	})
+
+	slices.SortStableFunc(routes, func(a, b *RoutableHandler) int {
+		return cmp.Compare(b.priority, a.priority)
+	})
+
+	return routes
 }
 
+// FirstMatch configures the router to send records to the first matching route only.
+//
+// Returns:
+//
+//	The router instance for method chaining
 func (h *router) FirstMatch() *router {
 	return &router{
 		handlers:   h.handlers,
 		firstMatch: true,
+		fallback:   h.fallback,
+		unrouted:   h.unrouted,
 	}
 }
 
@@ -99,8 +330,12 @@ var _ slog.Handler = (*RoutableHandler)(nil)
 //
 // @TODO: implement round robin strategy for load balancing across multiple handlers
 type RoutableHandler struct {
+	// name is an optional human-readable label of the route
+	name string
 	// predicates contains functions that determine if a record should be processed
 	predicates []func(ctx context.Context, r slog.Record) bool
+	// matchers contains matchers that determine if a record should be processed
+	matchers []Matcher
 	// handler is the underlying slog.Handler that processes matching records
 	handler slog.Handler
 	// groups tracks the current group hierarchy for proper attribute handling
@@ -109,6 +344,12 @@ type RoutableHandler struct {
 	attrs []slog.Attr
 	// skipPredicates indicates the caller MUST call isMatch(ctx, record) and MUST NOT invoke the handler for a given record if isMatch returns false.
 	skipPredicates bool
+	// priority orders routes, higher priorities are evaluated first
+	priority int
+	// continues lets the evaluation go on after a match, in FirstMatch mode
+	continues bool
+	// drop discards matching records and stops the evaluation
+	drop bool
 }
 
 // Enabled checks if the underlying handler is enabled for the given log level.
@@ -151,18 +392,25 @@ func (h *RoutableHandler) Handle(ctx context.Context, r slog.Record) error {
 }
 
 func (h *RoutableHandler) isMatch(ctx context.Context, r slog.Record) (slog.Record, bool) {
-	clone := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
-	clone.AddAttrs(
-		slogcommon.AppendRecordAttrsToAttrs(h.attrs, h.groups, &r)...,
-	)
+	view := newRecordView(r, h.groups, h.attrs)
+	return view.MergedRecord(), h.matchView(ctx, view)
+}
 
+// matchView returns true when all predicates and matchers match the view.
+func (h *RoutableHandler) matchView(ctx context.Context, view *RecordView) bool {
 	for _, predicate := range h.predicates {
-		if !predicate(ctx, clone) {
-			return clone, false
+		if !predicate(ctx, view.MergedRecord()) {
+			return false
+		}
+	}
+
+	for _, matcher := range h.matchers {
+		if !matcher.Match(ctx, view) {
+			return false
 		}
 	}
 
-	return clone, true
+	return true
 }
 
 // WithAttrs creates a new RoutableHandler with additional attributes.
@@ -180,11 +428,16 @@ func (h *RoutableHandler) isMatch(ctx context.Context, r slog.Record) (slog.Reco
 //	A new RoutableHandler with the additional attributes
 func (h *RoutableHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &RoutableHandler{
+		name:           h.name,
 		predicates:     h.predicates,
+		matchers:       h.matchers,
 		handler:        h.handler.WithAttrs(attrs),
 		groups:         slices.Clone(h.groups),
 		attrs:          slogcommon.AppendAttrsToGroup(h.groups, h.attrs, attrs...),
 		skipPredicates: h.skipPredicates,
+		priority:       h.priority,
+		continues:      h.continues,
+		drop:           h.drop,
 	}
 }
 
@@ -209,10 +462,40 @@ func (h *RoutableHandler) WithGroup(name string) slog.Handler {
 	}
 
 	return &RoutableHandler{
+		name:           h.name,
 		predicates:     h.predicates,
+		matchers:       h.matchers,
 		handler:        h.handler.WithGroup(name),
 		groups:         append(slices.Clone(h.groups), name),
 		attrs:          h.attrs,
 		skipPredicates: h.skipPredicates,
+		priority:       h.priority,
+		continues:      h.continues,
+		drop:           h.drop,
 	}
 }
+
+// Flush flushes the underlying handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *RoutableHandler) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.handler)
+}
+
+// Close closes the underlying handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *RoutableHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.handler)
+}
+
+// Inspect describes the route, its predicates and the underlying handler.
+// This method implements the Inspector interface.
+func (h *RoutableHandler) Inspect() HandlerNode {
+	if h.drop {
+		return HandlerNode{Type: "Drop", Route: routeLabel(h.predicates, h.matchers)}
+	}
+
+	node := inspectNode("Route", h.groups, h.attrs, h.handler)
+	node.Name = h.name
+	node.Route = routeLabel(h.predicates, h.matchers)
+	return node
+}
@@ -0,0 +1,222 @@
+package slogmulti
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// PredicateResult is the result of a route predicate or matcher, reported by Explain.
+type PredicateResult struct {
+	// Label is derived from the predicate function name (eg: "LevelIs()"), or from the matcher
+	Label string `json:"label"`
+	// Matched is the value returned by the predicate
+	Matched bool `json:"matched"`
+}
+
+// RouteExplanation reports how a route handles a record.
+type RouteExplanation struct {
+	// Index is the position of the route in evaluation order
+	Index int `json:"index"`
+	// Name is the optional name of the route (see AddNamed)
+	Name string `json:"name,omitempty"`
+	// Predicates contains the result of every predicate, then of every matcher, in order
+	Predicates []PredicateResult `json:"predicates"`
+	// Matched is true when every predicate and matcher matched
+	Matched bool `json:"matched"`
+	// Enabled is true when the handler of the route is enabled for the record level
+	Enabled bool `json:"enabled"`
+	// Drop is true for drop routes (see Drop)
+	Drop bool `json:"drop"`
+	// Skipped is true when a previous route stopped the evaluation
+	Skipped bool `json:"skipped"`
+	// Stop is true when the evaluation stops at this route
+	Stop bool `json:"stop"`
+	// Delivered is true when the handler of the route receives the record
+	Delivered bool `json:"delivered"`
+}
+
+// RouterExplanation reports how a router handles a record, route by route.
+type RouterExplanation struct {
+	// FirstMatch is true when the router stops at the first matching route
+	FirstMatch bool `json:"first_match"`
+	// Routes contains the explanation of every route, in order
+	Routes []RouteExplanation `json:"routes"`
+	// Unrouted is true when no route matched
+	Unrouted bool `json:"unrouted"`
+	// Default is the explanation of the default route, if any (see Default)
+	Default *RouteExplanation `json:"default,omitempty"`
+}
+
+// Delivered returns the routes receiving the record.
+func (e RouterExplanation) Delivered() []RouteExplanation {
+	routes := []RouteExplanation{}
+	for _, route := range e.Routes {
+		if route.Delivered {
+			routes = append(routes, route)
+		}
+	}
+	if e.Default != nil && e.Default.Delivered {
+		routes = append(routes, *e.Default)
+	}
+
+	return routes
+}
+
+// String renders the explanation as text, one line per route.
+func (e RouterExplanation) String() string {
+	var sb strings.Builder
+	for _, route := range e.Routes {
+		fmt.Fprintf(&sb, "#%d", route.Index)
+		if route.Name != "" {
+			fmt.Fprintf(&sb, " %q", route.Name)
+		}
+
+		predicates := make([]string, 0, len(route.Predicates))
+		for _, predicate := range route.Predicates {
+			predicates = append(predicates, fmt.Sprintf("%s=%t", predicate.Label, predicate.Matched))
+		}
+		fmt.Fprintf(&sb, " predicates=[%s]", strings.Join(predicates, " "))
+		fmt.Fprintf(&sb, " matched=%t enabled=%t delivered=%t", route.Matched, route.Enabled, route.Delivered)
+
+		if route.Drop {
+			sb.WriteString(" drop")
+		}
+		if route.Skipped {
+			sb.WriteString(" skipped")
+		}
+		if route.Stop {
+			sb.WriteString(" stop")
+		}
+		sb.WriteString("\n")
+	}
+
+	if e.Default != nil {
+		fmt.Fprintf(&sb, "default enabled=%t delivered=%t\n", e.Default.Enabled, e.Default.Delivered)
+	} else if e.Unrouted {
+		sb.WriteString("unrouted: dropped\n")
+	}
+
+	return sb.String()
+}
+
+// Explain reports how the router handles a record, without delivering it.
+// See RouterHandler.Explain.
+//
+// The record is explained without the attributes added with logger.With: use
+// RouterHandler.Explain on the handler of the logger to take them into account.
+func (h *router) Explain(ctx context.Context, record slog.Record) RouterExplanation {
+	return newRouterHandler(h.routes(), h.firstMatch, h.fallback, h.unrouted).Explain(ctx, record)
+}
+
+// Explain reports, for every route, whether each predicate matched, whether the
+// handler was enabled and whether FirstMatch stops at this route. When no route
+// matched, the default route is reported. The record is not delivered to any handler.
+//
+// Unlike routing, every predicate of every route is evaluated, so that the
+// report is complete. Predicates should be free of side effects. Predicates and
+// matchers see the attributes and groups added with WithAttrs and WithGroup.
+//
+// Example usage:
+//
+//	handler := slogmulti.Router().
+//	    AddNamed("errors", slackHandler, slogmulti.LevelIs(slog.LevelError)).
+//	    AddNamed("stdout", stdoutHandler).
+//	    Handler().(*slogmulti.RouterHandler)
+//
+//	record := slog.NewRecord(time.Now(), slog.LevelError, "boom", 0)
+//	explanation := handler.Explain(ctx, record)
+//	fmt.Print(explanation)
+//	// #0 "errors" predicates=[LevelIs()=true] matched=true enabled=true delivered=true
+//	// #1 "stdout" predicates=[] matched=true enabled=true delivered=true
+//
+// Args:
+//
+//	ctx: The context of the logging operation
+//	record: The record to route
+//
+// Returns:
+//
+//	The explanation of the routing decision
+func (h *RouterHandler) Explain(ctx context.Context, record slog.Record) RouterExplanation {
+	view := newRecordView(record, h.groups, h.attrs)
+	routes := make([]RouteExplanation, len(h.routes))
+	evaluated := make([]bool, len(h.routes))
+
+	matched, stop := h.route(
+		ctx,
+		record.Level,
+		h.positions,
+		func(i int) bool {
+			routes[i] = h.routes[i].explain(ctx, view)
+			evaluated[i] = true
+			return routes[i].Matched
+		},
+		func(i int) {
+			routes[i].Delivered = true
+		},
+	)
+
+	for i := range routes {
+		if !evaluated[i] {
+			routes[i] = h.routes[i].explain(ctx, view)
+			routes[i].Skipped = true
+		}
+		routes[i].Index = i
+	}
+	if stop >= 0 {
+		routes[stop].Stop = true
+	}
+
+	explanation := RouterExplanation{
+		FirstMatch: h.firstMatch,
+		Routes:     routes,
+		Unrouted:   !matched,
+	}
+
+	if h.fallback != nil {
+		enabled := h.fallback.Enabled(ctx, record.Level)
+		explanation.Default = &RouteExplanation{
+			Index:      len(h.routes),
+			Name:       "default",
+			Predicates: []PredicateResult{},
+			Matched:    !matched,
+			Enabled:    enabled,
+			Delivered:  !matched && enabled,
+		}
+	}
+
+	return explanation
+}
+
+// explain evaluates every predicate and matcher of the route, without delivering the record.
+func (h *RoutableHandler) explain(ctx context.Context, view *RecordView) RouteExplanation {
+	route := RouteExplanation{
+		Name:       h.name,
+		Predicates: make([]PredicateResult, 0, len(h.predicates)+len(h.matchers)),
+		Matched:    true,
+		Enabled:    h.handler.Enabled(ctx, view.Level()),
+		Drop:       h.drop,
+	}
+
+	for _, predicate := range h.predicates {
+		matched := predicate(ctx, view.MergedRecord())
+		route.Matched = route.Matched && matched
+		route.Predicates = append(route.Predicates, PredicateResult{
+			Label:   funcLabel(predicate),
+			Matched: matched,
+		})
+	}
+
+	for _, matcher := range h.matchers {
+		matched := matcher.Match(ctx, view)
+		route.Matched = route.Matched && matched
+		route.Predicates = append(route.Predicates, PredicateResult{
+			Label:   matcherLabel(matcher),
+			Matched: matched,
+		})
+	}
+
+	return route
+}
@@ -0,0 +1,125 @@
+package slogmulti
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterExplain(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	errorsSink := &countingHandler{}
+	audit := &countingHandler{}
+	stdout := &countingHandler{}
+	warnOnly := slog.NewTextHandler(nil, &slog.HandlerOptions{Level: slog.LevelWarn})
+
+	r := Router().
+		AddNamed("errors", errorsSink, LevelIs(slog.LevelError)).
+		AddNamed("audit", audit, LevelIs(slog.LevelInfo, slog.LevelError), AttrValueIs("scope", "audit")).
+		AddNamed("warn-only", warnOnly).
+		Add(stdout)
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "login", 0)
+	record.AddAttrs(slog.String("scope", "audit"))
+
+	explanation := r.Explain(context.Background(), record)
+	is.False(explanation.FirstMatch)
+	is.Equal([]RouteExplanation{
+		{Index: 0, Name: "errors", Predicates: []PredicateResult{{Label: "LevelIs()", Matched: false}}, Matched: false, Enabled: true},
+		{Index: 1, Name: "audit", Predicates: []PredicateResult{{Label: "LevelIs()", Matched: true}, {Label: "AttrValueIs()", Matched: true}}, Matched: true, Enabled: true, Delivered: true},
+		{Index: 2, Name: "warn-only", Predicates: []PredicateResult{}, Matched: true, Enabled: false},
+		{Index: 3, Predicates: []PredicateResult{}, Matched: true, Enabled: true, Delivered: true},
+	}, explanation.Routes)
+	is.Len(explanation.Delivered(), 2)
+	is.Equal(`#0 "errors" predicates=[LevelIs()=false] matched=false enabled=true delivered=false
+#1 "audit" predicates=[LevelIs()=true AttrValueIs()=true] matched=true enabled=true delivered=true
+#2 "warn-only" predicates=[] matched=true enabled=false delivered=false
+#3 predicates=[] matched=true enabled=true delivered=true
+`, explanation.String())
+
+	// no record is delivered
+	is.EqualValues(0, errorsSink.handleCount.Load())
+	is.EqualValues(0, audit.handleCount.Load())
+	is.EqualValues(0, stdout.handleCount.Load())
+
+	output, err := json.Marshal(explanation.Routes[0])
+	is.NoError(err)
+	is.JSONEq(`{"index":0,"name":"errors","predicates":[{"label":"LevelIs()","matched":false}],"matched":false,"enabled":true,"drop":false,"skipped":false,"stop":false,"delivered":false}`, string(output))
+}
+
+func TestRouterExplainFirstMatch(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	warnOnly := slog.NewTextHandler(nil, &slog.HandlerOptions{Level: slog.LevelWarn})
+
+	r := Router().
+		AddNamed("errors", &countingHandler{}, LevelIs(slog.LevelError)).
+		AddNamed("warn-only", warnOnly, MessageContains("login")).
+		AddNamed("stdout", &countingHandler{}).
+		FirstMatch()
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "login", 0)
+
+	explanation := r.Explain(context.Background(), record)
+	is.True(explanation.FirstMatch)
+	is.Len(explanation.Routes, 3)
+	is.False(explanation.Routes[0].Matched)
+	is.False(explanation.Routes[0].Stop)
+	// the matching route stops the evaluation, even when its handler is disabled
+	is.True(explanation.Routes[1].Matched)
+	is.False(explanation.Routes[1].Enabled)
+	is.True(explanation.Routes[1].Stop)
+	is.False(explanation.Routes[1].Delivered)
+	is.True(explanation.Routes[2].Matched)
+	is.True(explanation.Routes[2].Skipped)
+	is.False(explanation.Routes[2].Delivered)
+	is.Empty(explanation.Delivered())
+}
+
+func TestRouterHandlerExplain(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	audit := &countingHandler{}
+	stdout := &countingHandler{}
+
+	r := Router().
+		AddNamed("audit", audit, AttrValueIs("scope", "audit")).
+		AddMatchers(stdout, MatchAttr("http.status", 500)).
+		FirstMatch()
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "login", 0)
+
+	// the builder ignores the attributes added with logger.With
+	explanation := r.Explain(context.Background(), record)
+	is.True(explanation.Unrouted)
+
+	logger := slog.New(r.Handler()).With("scope", "audit").WithGroup("http").With("status", 500)
+	handler, ok := logger.Handler().(*RouterHandler)
+	is.True(ok)
+
+	explanation = handler.Explain(context.Background(), record)
+	is.False(explanation.Unrouted)
+	is.Equal(`#0 "audit" predicates=[AttrValueIs()=true] matched=true enabled=true delivered=true stop
+#1 predicates=[MatchAttr("http.status", 500)=true] matched=true enabled=true delivered=false skipped
+`, explanation.String())
+
+	// no record is delivered
+	is.EqualValues(0, audit.handleCount.Load())
+	is.EqualValues(0, stdout.handleCount.Load())
+}
+
+func TestRouterAddNamedInspect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	handler := Router().AddNamed("errors", &noopHandler{}, LevelIs(slog.LevelError)).Handler()
+	is.Equal("Router\n└── Route name=\"errors\" route=\"LevelIs()\"\n    └── *slogmulti.noopHandler\n", Describe(handler))
+}
@@ -0,0 +1,276 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+
+	"github.com/samber/lo"
+	slogcommon "github.com/samber/slog-common"
+)
+
+// Ensure RouterHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*RouterHandler)(nil)
+
+// RouterHandler routes records to matching routes, and sends records that no
+// route matched to a default handler. It is built by Router().Handler().
+//
+// Attributes and groups received via WithAttrs and WithGroup are tracked once
+// by the router: a single RecordView is built per record and shared by the
+// predicates and matchers of every route.
+type RouterHandler struct {
+	// routes contains the routes, in evaluation order
+	routes []*RoutableHandler
+	// firstMatch stops the evaluation at the first matching route
+	firstMatch bool
+	// fallback receives records that no route matched (optional)
+	fallback slog.Handler
+	// unrouted counts records that no route matched (optional)
+	unrouted *atomic.Int64
+	// groups tracks the current group hierarchy, shared by all routes
+	groups []string
+	// attrs contains accumulated attributes, shared by all routes
+	attrs []slog.Attr
+	// index selects candidate routes, for large routing tables (optional)
+	index *routeIndex
+	// positions contains the positions of all routes, for linear scans
+	positions []int
+}
+
+func newRouterHandler(routes []*RoutableHandler, firstMatch bool, fallback slog.Handler, unrouted *atomic.Int64) *RouterHandler {
+	return &RouterHandler{
+		routes:     routes,
+		firstMatch: firstMatch,
+		fallback:   fallback,
+		unrouted:   unrouted,
+		groups:     []string{},
+		attrs:      []slog.Attr{},
+		index:      newRouteIndex(routes),
+		positions:  lo.Range(len(routes)),
+	}
+}
+
+// Enabled checks if any route, or the default route, is enabled for the given log level.
+// This method implements the slog.Handler interface requirement.
+func (h *RouterHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	for i := range h.routes {
+		if h.routes[i].Enabled(ctx, l) {
+			return true
+		}
+	}
+
+	return h.fallback != nil && h.fallback.Enabled(ctx, l)
+}
+
+// Handle sends the record to matching routes, or to the default route when no route matched.
+// This method implements the slog.Handler interface requirement.
+//
+// Large routing tables are indexed: routes with a MatchAttr, MatchMessage or
+// MatchLevel matcher are only evaluated when the record may match them.
+//
+// Routes are evaluated by descending priority. A route matches when all of its
+// predicates return true, even if its handler is not enabled for the record level.
+// In FirstMatch mode, the evaluation stops at the first matching route, unless the
+// route is marked with Continue. Drop routes discard matching records and stop the
+// evaluation.
+func (h *RouterHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	view := newRecordView(r, h.groups, h.attrs)
+
+	positions := h.positions
+	if h.index != nil {
+		var buf [16]int
+		positions = h.index.candidates(view, buf[:0])
+	}
+
+	matched, _ := h.route(
+		ctx,
+		r.Level,
+		positions,
+		func(i int) bool {
+			return h.routes[i].matchView(ctx, view)
+		},
+		func(i int) {
+			err := try(func() error {
+				return h.routes[i].handler.Handle(ctx, r.Clone())
+			})
+			if err != nil {
+				errs = append(errs, err)
+			}
+		},
+	)
+
+	if !matched {
+		if h.unrouted != nil {
+			h.unrouted.Add(1)
+		}
+
+		if h.fallback != nil && h.fallback.Enabled(ctx, r.Level) {
+			err := try(func() error {
+				return h.fallback.Handle(ctx, r.Clone())
+			})
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// route evaluates the routes at the given positions, in order, and calls deliver
+// for every matching route whose handler is enabled. The evaluation stops at the
+// first matching drop route, and in FirstMatch mode at the first matching route
+// not marked with Continue. It is shared by Handle and Explain.
+//
+// It returns whether a route matched, and the position of the route that stopped
+// the evaluation, or -1.
+func (h *RouterHandler) route(ctx context.Context, level slog.Level, positions []int, match func(i int) bool, deliver func(i int)) (bool, int) {
+	matched := false
+
+	for _, i := range positions {
+		if !match(i) {
+			continue
+		}
+
+		matched = true
+		if h.routes[i].drop {
+			return matched, i
+		}
+
+		if h.routes[i].Enabled(ctx, level) {
+			deliver(i)
+		}
+
+		// FirstMatch stops at the first matching route, even when the handler is disabled
+		if h.firstMatch && !h.routes[i].continues {
+			return matched, i
+		}
+	}
+
+	return matched, -1
+}
+
+// WithAttrs creates a new RouterHandler with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *RouterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	routes := lo.Map(h.routes, func(route *RoutableHandler, _ int) *RoutableHandler {
+		return route.withHandler(route.handler.WithAttrs(slices.Clone(attrs)))
+	})
+
+	var fallback slog.Handler
+	if h.fallback != nil {
+		fallback = h.fallback.WithAttrs(slices.Clone(attrs))
+	}
+
+	return &RouterHandler{
+		routes:     routes,
+		firstMatch: h.firstMatch,
+		fallback:   fallback,
+		unrouted:   h.unrouted,
+		groups:     slices.Clone(h.groups),
+		attrs:      slogcommon.AppendAttrsToGroup(h.groups, h.attrs, attrs...),
+		index:      h.index,
+		positions:  h.positions,
+	}
+}
+
+// WithGroup creates a new RouterHandler with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *RouterHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	routes := lo.Map(h.routes, func(route *RoutableHandler, _ int) *RoutableHandler {
+		return route.withHandler(route.handler.WithGroup(name))
+	})
+
+	var fallback slog.Handler
+	if h.fallback != nil {
+		fallback = h.fallback.WithGroup(name)
+	}
+
+	return &RouterHandler{
+		routes:     routes,
+		firstMatch: h.firstMatch,
+		fallback:   fallback,
+		unrouted:   h.unrouted,
+		groups:     append(slices.Clone(h.groups), name),
+		attrs:      h.attrs,
+		index:      h.index,
+		positions:  h.positions,
+	}
+}
+
+// Flush flushes the routes and the default route.
+// This method implements the Flusher interface.
+func (h *RouterHandler) Flush(ctx context.Context) error {
+	return flushHandlers(ctx, h.handlers()...)
+}
+
+// Close closes the routes and the default route.
+// This method implements the Closer interface.
+func (h *RouterHandler) Close(ctx context.Context) error {
+	return closeHandlers(ctx, h.handlers()...)
+}
+
+// Inspect describes the router, its routes and its default route.
+// This method implements the Inspector interface.
+func (h *RouterHandler) Inspect() HandlerNode {
+	node := inspectNode("Router", h.groups, h.attrs)
+	node.Children = inspectHandlers(h.routes...)
+	if h.firstMatch {
+		node.Type = "FirstMatch"
+	}
+
+	if h.fallback != nil {
+		node.Children = append(node.Children, HandlerNode{
+			Type:     "Default",
+			Children: inspectHandlers(h.fallback),
+		})
+	}
+
+	return node
+}
+
+// withHandler returns a copy of the route, with another handler. Groups and
+// attributes are tracked by the RouterHandler, not by its routes.
+func (h *RoutableHandler) withHandler(handler slog.Handler) *RoutableHandler {
+	return &RoutableHandler{
+		name:           h.name,
+		predicates:     h.predicates,
+		matchers:       h.matchers,
+		handler:        handler,
+		groups:         h.groups,
+		attrs:          h.attrs,
+		skipPredicates: h.skipPredicates,
+		priority:       h.priority,
+		continues:      h.continues,
+		drop:           h.drop,
+	}
+}
+
+func (h *RouterHandler) handlers() []slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.routes)+1)
+	for _, route := range h.routes {
+		handlers = append(handlers, route)
+	}
+	if h.fallback != nil {
+		handlers = append(handlers, h.fallback)
+	}
+
+	return handlers
+}
+
+// dropHandler is the handler of drop routes. It is never enabled, so that drop
+// routes do not enable levels on the router.
+type dropHandler struct{}
+
+func (dropHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (dropHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h dropHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h dropHandler) WithGroup(string) slog.Handler           { return h }
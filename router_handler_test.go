@@ -0,0 +1,254 @@
+package slogmulti
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterDefault(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	errorsBuf := bytes.NewBufferString("")
+	infoBuf := bytes.NewBufferString("")
+	defaultBuf := bytes.NewBufferString("")
+	var unrouted atomic.Int64
+
+	handler := Router().
+		Add(slog.NewTextHandler(errorsBuf, &slog.HandlerOptions{ReplaceAttr: remoteTimeReplaceAttr}), LevelIs(slog.LevelError)).
+		Add(slog.NewTextHandler(infoBuf, &slog.HandlerOptions{ReplaceAttr: remoteTimeReplaceAttr}), LevelIs(slog.LevelInfo)).
+		Default(slog.NewTextHandler(defaultBuf, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: remoteTimeReplaceAttr})).
+		CountUnrouted(&unrouted).
+		Handler()
+	is.IsType(&RouterHandler{}, handler)
+
+	logger := slog.New(handler).With("a", 1).WithGroup("g")
+	logger.Error("error", "foo", "bar")
+	logger.Info("info")
+	logger.Warn("warn", "foo", "bar")
+	logger.Debug("debug")
+
+	is.Equal("level=ERROR msg=error a=1 g.foo=bar\n", errorsBuf.String())
+	is.Equal("level=INFO msg=info a=1\n", infoBuf.String())
+	is.Equal("level=WARN msg=warn a=1 g.foo=bar\nlevel=DEBUG msg=debug a=1\n", defaultBuf.String())
+	is.EqualValues(2, unrouted.Load())
+}
+
+func TestRouterDefaultFirstMatch(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	first := &countingHandler{}
+	second := &countingHandler{}
+	fallback := &countingHandler{}
+	var unrouted atomic.Int64
+
+	logger := slog.New(
+		Router().
+			Add(first, AttrValueIs("scope", "auth")).
+			Add(second, LevelIs(slog.LevelError)).
+			Default(fallback).
+			CountUnrouted(&unrouted).
+			FirstMatch().
+			Handler(),
+	)
+
+	logger.Error("both", "scope", "auth")
+	logger.Error("second")
+	logger.Info("none")
+
+	is.EqualValues(1, first.handleCount.Load())
+	is.EqualValues(1, second.handleCount.Load())
+	is.EqualValues(1, fallback.handleCount.Load())
+	is.EqualValues(1, unrouted.Load())
+}
+
+func TestRouterUnroutedWithoutDefault(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	var unrouted atomic.Int64
+	sink := &countingHandler{}
+
+	logger := slog.New(Router().Add(sink, LevelIs(slog.LevelError)).CountUnrouted(&unrouted).Handler())
+	logger.Info("dropped")
+	logger.Error("routed")
+
+	is.EqualValues(1, sink.handleCount.Load())
+	is.EqualValues(1, unrouted.Load())
+
+	// a matching route with a disabled handler is not unrouted
+	logger = slog.New(Router().Add(newCountingHandler(slog.LevelError), LevelIs(slog.LevelWarn)).Default(&countingHandler{}).CountUnrouted(&unrouted).Handler())
+	logger.Warn("disabled route")
+	is.EqualValues(1, unrouted.Load())
+}
+
+func TestRouterDefaultErrors(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	err := errors.New("boom")
+	handler := Router().
+		Add(&countingHandler{}, LevelIs(slog.LevelError)).
+		Default(&errorHandler{err: err}).
+		Handler()
+
+	is.ErrorIs(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)), err)
+	is.NoError(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelError, "hello", 0)))
+}
+
+func TestRouterDefaultExplainAndInspect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	r := Router().
+		AddNamed("errors", &noopHandler{}, LevelIs(slog.LevelError)).
+		Default(&noopHandler{})
+
+	explanation := r.Explain(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+	is.True(explanation.Unrouted)
+	is.NotNil(explanation.Default)
+	is.True(explanation.Default.Delivered)
+	is.Len(explanation.Delivered(), 1)
+	is.Equal("#0 \"errors\" predicates=[LevelIs()=false] matched=false enabled=true delivered=false\ndefault enabled=true delivered=true\n", explanation.String())
+
+	explanation = r.Explain(context.Background(), slog.NewRecord(time.Now(), slog.LevelError, "hello", 0))
+	is.False(explanation.Unrouted)
+	is.False(explanation.Default.Delivered)
+
+	is.Equal(`Router
+├── Route name="errors" route="LevelIs()"
+│   └── *slogmulti.noopHandler
+└── Default
+    └── *slogmulti.noopHandler
+`, Describe(r.Handler()))
+}
+
+func TestRouterPriorityAndContinue(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	audit := &countingHandler{}
+	errorsSink := &countingHandler{}
+	stdout := &countingHandler{}
+
+	logger := slog.New(
+		Router().
+			Add(stdout).
+			AddRoute(Route{Name: "errors", Handler: errorsSink, Predicates: []func(context.Context, slog.Record) bool{LevelIs(slog.LevelError)}, Priority: 10}).
+			AddRoute(Route{Name: "audit", Handler: audit, Predicates: []func(context.Context, slog.Record) bool{AttrValueIs("scope", "audit")}, Priority: 20, Continue: true}).
+			FirstMatch().
+			Handler(),
+	)
+
+	logger.Info("hello")                     // stdout
+	logger.Error("boom")                     // errors
+	logger.Info("login", "scope", "audit")   // audit, then stdout
+	logger.Error("denied", "scope", "audit") // audit, then errors
+
+	is.EqualValues(2, audit.handleCount.Load())
+	is.EqualValues(2, errorsSink.handleCount.Load())
+	is.EqualValues(2, stdout.handleCount.Load())
+}
+
+func TestRouterDrop(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	stdout := &countingHandler{}
+	fallback := &countingHandler{}
+	var unrouted atomic.Int64
+
+	r := Router().
+		Add(stdout, LevelIs(slog.LevelInfo)).
+		DropWithPriority(100, MessageIs("GET /healthz")).
+		Default(fallback).
+		CountUnrouted(&unrouted)
+
+	logger := slog.New(r.Handler())
+	logger.Info("GET /healthz")
+	logger.Warn("GET /healthz")
+	logger.Info("GET /users")
+	logger.Warn("GET /users")
+
+	is.EqualValues(1, stdout.handleCount.Load())
+	is.EqualValues(1, fallback.handleCount.Load())
+	is.EqualValues(1, unrouted.Load())
+
+	// drop routes do not enable levels
+	is.False(Router().Drop().CountUnrouted(&unrouted).Handler().Enabled(context.Background(), slog.LevelInfo))
+
+	explanation := r.Explain(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "GET /healthz", 0))
+	is.False(explanation.Unrouted)
+	is.Empty(explanation.Delivered())
+	is.Equal(`#0 "drop" predicates=[MessageIs()=true] matched=true enabled=false delivered=false drop stop
+#1 predicates=[LevelIs()=true] matched=true enabled=true delivered=false skipped
+default enabled=true delivered=false
+`, explanation.String())
+
+	is.Equal(`Router
+├── Drop route="MessageIs()"
+├── Route route="LevelIs()"
+│   └── *slogmulti.countingHandler
+└── Default
+    └── *slogmulti.countingHandler
+`, Describe(r.Handler()))
+}
+
+func TestRouterPriorityFanout(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	order := []string{}
+	record := func(name string) slog.Handler {
+		return NewHandleInlineHandler(func(ctx context.Context, groups []string, attrs []slog.Attr, r slog.Record) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	logger := slog.New(
+		Router().
+			AddRoute(Route{Name: "low", Handler: record("low"), Priority: -1}).
+			AddRoute(Route{Name: "a", Handler: record("a")}).
+			AddRoute(Route{Name: "high", Handler: record("high"), Priority: 1}).
+			AddRoute(Route{Name: "b", Handler: record("b")}).
+			Handler(),
+	)
+	logger.Info("hello")
+
+	is.Equal([]string{"high", "a", "b", "low"}, order)
+}
+
+func TestRouterPriorityExtremes(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	order := []string{}
+	record := func(name string) slog.Handler {
+		return NewHandleInlineHandler(func(ctx context.Context, groups []string, attrs []slog.Attr, r slog.Record) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+
+	// b.priority - a.priority would overflow
+	logger := slog.New(
+		Router().
+			AddRoute(Route{Name: "min", Handler: record("min"), Priority: math.MinInt}).
+			AddRoute(Route{Name: "one", Handler: record("one"), Priority: 1}).
+			AddRoute(Route{Name: "max", Handler: record("max"), Priority: math.MaxInt}).
+			Handler(),
+	)
+	logger.Info("hello")
+
+	is.Equal([]string{"max", "one", "min"}, order)
+}
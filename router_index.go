@@ -0,0 +1,154 @@
+package slogmulti
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// routeIndexThreshold is the minimum number of routes for which a routing index is built.
+// Below this size, a linear scan is faster than collecting candidates.
+const routeIndexThreshold = 8
+
+// routeIndex selects the routes that may match a record, in O(1), using hash maps
+// keyed by the value of an indexable matcher of each route (see MatchAttr,
+// MatchMessage and MatchLevel). Routes without indexable matcher are opaque and
+// always evaluated: predicates, such as AttrValueIs, are plain functions whose
+// arguments cannot be read.
+//
+// Candidates are then evaluated in route order, with all their predicates and
+// matchers, so that the routing decision is identical to a linear scan.
+type routeIndex struct {
+	// attrs maps an attribute key and value to route positions
+	attrs map[string]map[attrIndexKey][]int
+	// messages maps a message to route positions
+	messages map[string][]int
+	// levels maps a level to route positions
+	levels map[slog.Level][]int
+	// opaque contains the positions of routes that cannot be indexed
+	opaque []int
+}
+
+// attrIndexKey is a hashable representation of a slog.Value. Only kinds compared
+// with == by attrValueEquals are indexed: floats (NaN, -0), times and KindAny
+// values (possibly non-comparable) are not.
+type attrIndexKey struct {
+	kind slog.Kind
+	str  string
+	num  uint64
+}
+
+func newAttrIndexKey(value slog.Value) (attrIndexKey, bool) {
+	switch value.Kind() {
+	case slog.KindString:
+		return attrIndexKey{kind: slog.KindString, str: value.String()}, true
+	case slog.KindInt64:
+		return attrIndexKey{kind: slog.KindInt64, num: uint64(value.Int64())}, true
+	case slog.KindUint64:
+		return attrIndexKey{kind: slog.KindUint64, num: value.Uint64()}, true
+	case slog.KindDuration:
+		return attrIndexKey{kind: slog.KindDuration, num: uint64(value.Duration())}, true
+	case slog.KindBool:
+		if value.Bool() {
+			return attrIndexKey{kind: slog.KindBool, num: 1}, true
+		}
+		return attrIndexKey{kind: slog.KindBool}, true
+	}
+
+	return attrIndexKey{}, false
+}
+
+// newRouteIndex builds an index for the routes, or returns nil when the routing
+// table is too small or when no route is indexable.
+func newRouteIndex(routes []*RoutableHandler) *routeIndex {
+	if len(routes) < routeIndexThreshold {
+		return nil
+	}
+
+	index := &routeIndex{
+		attrs:    map[string]map[attrIndexKey][]int{},
+		messages: map[string][]int{},
+		levels:   map[slog.Level][]int{},
+		opaque:   []int{},
+	}
+
+	indexed := 0
+	for i, route := range routes {
+		if index.add(i, route) {
+			indexed++
+		} else {
+			index.opaque = append(index.opaque, i)
+		}
+	}
+
+	if indexed == 0 {
+		return nil
+	}
+
+	return index
+}
+
+// add indexes the route with its most selective matcher: attribute equality,
+// then message, then level.
+func (idx *routeIndex) add(position int, route *RoutableHandler) bool {
+	if route.drop {
+		return false
+	}
+
+	for _, matcher := range route.matchers {
+		if m, ok := matcher.(attrMatcher); ok {
+			key, ok := newAttrIndexKey(m.value)
+			if !ok {
+				continue
+			}
+
+			if idx.attrs[m.key] == nil {
+				idx.attrs[m.key] = map[attrIndexKey][]int{}
+			}
+			idx.attrs[m.key][key] = append(idx.attrs[m.key][key], position)
+			return true
+		}
+	}
+
+	for _, matcher := range route.matchers {
+		if m, ok := matcher.(messageMatcher); ok {
+			idx.messages[m.message] = append(idx.messages[m.message], position)
+			return true
+		}
+	}
+
+	for _, matcher := range route.matchers {
+		if m, ok := matcher.(levelMatcher); ok {
+			levels := slices.Clone(m.levels)
+			slices.Sort(levels)
+			for _, level := range slices.Compact(levels) {
+				idx.levels[level] = append(idx.levels[level], position)
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+// candidates appends the positions of the routes that may match the view to buf,
+// in route order.
+func (idx *routeIndex) candidates(view *RecordView, buf []int) []int {
+	buf = append(buf, idx.opaque...)
+	buf = append(buf, idx.levels[view.Level()]...)
+	buf = append(buf, idx.messages[view.Message()]...)
+
+	for key, values := range idx.attrs {
+		value, ok := view.Value(key)
+		if !ok {
+			continue
+		}
+
+		if k, ok := newAttrIndexKey(value.Resolve()); ok {
+			buf = append(buf, values[k]...)
+		}
+	}
+
+	// each route is indexed once, so candidates are unique
+	slices.Sort(buf)
+	return buf
+}
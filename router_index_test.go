@@ -0,0 +1,138 @@
+package slogmulti
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingRoute appends its name to a shared log when it handles a record.
+func recordingRoute(name string, log *[]string) slog.Handler {
+	return NewHandleInlineHandler(func(ctx context.Context, groups []string, attrs []slog.Attr, r slog.Record) error {
+		*log = append(*log, name)
+		return nil
+	})
+}
+
+func buildIndexTestRouter(log *[]string, firstMatch bool) *router {
+	r := Router()
+	for i := 0; i < 10; i++ {
+		r = r.AddRoute(Route{
+			Name:     fmt.Sprintf("tenant-%d", i),
+			Handler:  recordingRoute(fmt.Sprintf("tenant-%d", i), log),
+			Matchers: []Matcher{MatchAttr("tenant", fmt.Sprintf("tenant-%d", i))},
+			Continue: i%3 == 0,
+		})
+	}
+
+	r = r.
+		AddMatchers(recordingRoute("count", log), MatchAttr("count", 3), MatchLevel(slog.LevelInfo)).
+		AddMatchers(recordingRoute("flag", log), MatchAttr("flag", true)).
+		AddMatchers(recordingRoute("float", log), MatchAttr("ratio", 0.5)).
+		AddMatchers(recordingRoute("nan", log), MatchAttr("ratio", math.NaN())).
+		AddMatchers(recordingRoute("tags", log), MatchAttr("tags", []string{"a"})).
+		AddMatchers(recordingRoute("nested", log), MatchAttr("user.id", "u1")).
+		AddMatchers(recordingRoute("message", log), MatchMessage("hello")).
+		AddMatchers(recordingRoute("errors", log), MatchLevel(slog.LevelError, slog.LevelError, slog.LevelWarn)).
+		AddMatchers(recordingRoute("func", log), MatcherFunc(func(ctx context.Context, view *RecordView) bool { return view.Message() == "func" })).
+		AddRoute(Route{Name: "priority", Handler: recordingRoute("priority", log), Matchers: []Matcher{MatchAttr("tenant", "tenant-5")}, Priority: 1}).
+		DropWithPriority(2, MessageIs("drop")).
+		Add(recordingRoute("legacy", log), AttrValueIs("tenant", "tenant-7")).
+		Add(recordingRoute("legacy-message", log), MessageIs("other"), LevelIs(slog.LevelWarn)).
+		Default(recordingRoute("default", log))
+
+	if firstMatch {
+		r = r.FirstMatch()
+	}
+
+	return r
+}
+
+func indexTestRecords() []slog.Record {
+	records := []slog.Record{}
+	for _, level := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError} {
+		for _, msg := range []string{"hello", "drop", "func", "other"} {
+			for _, attrs := range [][]slog.Attr{
+				nil,
+				{slog.String("tenant", "tenant-0")},
+				{slog.String("tenant", "tenant-5")},
+				{slog.String("tenant", "tenant-7"), slog.Int("count", 3)},
+				{slog.String("tenant", "tenant-1"), slog.String("tenant", "tenant-3")},
+				{slog.String("tenant", "tenant-1"), slog.String("tenant", "tenant-1")},
+				{slog.Int("tenant", 3), slog.Bool("flag", true)},
+				{slog.Float64("ratio", 0.5), slog.Any("tags", []string{"a"})},
+				{slog.Float64("ratio", math.NaN()), slog.Group("user", slog.String("id", "u1"))},
+				{slog.String("count", "3"), slog.Bool("flag", false)},
+			} {
+				r := slog.NewRecord(time.Now(), level, msg, 0)
+				r.AddAttrs(attrs...)
+				records = append(records, r)
+			}
+		}
+	}
+
+	return records
+}
+
+func TestRouterIndexMatchesLinearScan(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	for _, firstMatch := range []bool{false, true} {
+		indexedLog := []string{}
+		linearLog := []string{}
+
+		indexed := buildIndexTestRouter(&indexedLog, firstMatch).Handler().(*RouterHandler)
+		linear := buildIndexTestRouter(&linearLog, firstMatch).Handler().(*RouterHandler)
+		linear.index = nil
+		is.NotNil(indexed.index)
+
+		for _, record := range indexTestRecords() {
+			indexedLog = indexedLog[:0]
+			linearLog = linearLog[:0]
+
+			is.NoError(indexed.Handle(context.Background(), record))
+			is.NoError(linear.Handle(context.Background(), record))
+			is.Equal(linearLog, indexedLog, "first_match=%t level=%s msg=%s", firstMatch, record.Level, record.Message)
+		}
+	}
+}
+
+func TestRouterIndexWithAttrs(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	log := []string{}
+	handler := buildIndexTestRouter(&log, false).Handler().WithAttrs([]slog.Attr{slog.String("tenant", "tenant-2")})
+	is.NotNil(handler.(*RouterHandler).index)
+
+	logger := slog.New(handler)
+	logger.Info("other")
+	is.Equal([]string{"tenant-2"}, log)
+
+	// record attributes nested in a group do not match top-level keys
+	log = log[:0]
+	logger.WithGroup("g").Info("other", "tenant", "tenant-4")
+	is.Equal([]string{"tenant-2"}, log)
+}
+
+func TestRouteIndexThreshold(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	small := Router().AddMatchers(&noopHandler{}, MatchAttr("tenant", "a")).Handler().(*RouterHandler)
+	is.Nil(small.index)
+
+	r := Router()
+	for i := 0; i < routeIndexThreshold; i++ {
+		r = r.Add(&noopHandler{}, LevelIs(slog.LevelInfo))
+	}
+	// opaque predicates only
+	is.Nil(r.Handler().(*RouterHandler).index)
+	is.NotNil(r.AddMatchers(&noopHandler{}, MatchLevel(slog.LevelInfo)).Handler().(*RouterHandler).index)
+}
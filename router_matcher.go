@@ -0,0 +1,159 @@
+package slogmulti
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// Matcher decides whether a record should be routed to a route. Unlike
+// `func(ctx, slog.Record) bool` predicates, matchers read a RecordView shared by
+// every route of the router, so that attributes are merged once per record.
+type Matcher interface {
+	Match(ctx context.Context, view *RecordView) bool
+}
+
+// MatcherFunc is an adapter to use an ordinary function as a Matcher.
+type MatcherFunc func(ctx context.Context, view *RecordView) bool
+
+// Match calls f(ctx, view).
+// This method implements the Matcher interface.
+func (f MatcherFunc) Match(ctx context.Context, view *RecordView) bool {
+	return f(ctx, view)
+}
+
+// Predicate adapts a `func(ctx, slog.Record) bool` predicate into a Matcher.
+// The predicate receives the merged record of the view, built once per record.
+func Predicate(predicate func(ctx context.Context, r slog.Record) bool) Matcher {
+	return predicateMatcher{predicate: predicate}
+}
+
+type predicateMatcher struct {
+	predicate func(ctx context.Context, r slog.Record) bool
+}
+
+func (m predicateMatcher) Match(ctx context.Context, view *RecordView) bool {
+	return m.predicate(ctx, view.MergedRecord())
+}
+
+func (m predicateMatcher) String() string {
+	return funcLabel(m.predicate)
+}
+
+// MatchLevel returns a Matcher checking if the record level is in the given levels.
+//
+// Example usage:
+//
+//	r := slogmulti.Router().
+//	    AddMatchers(consoleHandler, slogmulti.MatchLevel(slog.LevelInfo)).
+//	    AddMatchers(fileHandler, slogmulti.MatchLevel(slog.LevelError)).
+//	    Handler()
+func MatchLevel(levels ...slog.Level) Matcher {
+	return levelMatcher{levels: levels}
+}
+
+type levelMatcher struct {
+	levels []slog.Level
+}
+
+func (m levelMatcher) Match(_ context.Context, view *RecordView) bool {
+	for _, level := range m.levels {
+		if view.Level() == level {
+			return true
+		}
+	}
+	return false
+}
+
+func (m levelMatcher) String() string {
+	levels := make([]string, 0, len(m.levels))
+	for _, level := range m.levels {
+		levels = append(levels, level.String())
+	}
+	return fmt.Sprintf("MatchLevel(%s)", strings.Join(levels, ", "))
+}
+
+// MatchMessage returns a Matcher checking if the record message is exactly msg.
+func MatchMessage(msg string) Matcher {
+	return messageMatcher{message: msg}
+}
+
+type messageMatcher struct {
+	message string
+}
+
+func (m messageMatcher) Match(_ context.Context, view *RecordView) bool {
+	return view.Message() == m.message
+}
+
+func (m messageMatcher) String() string {
+	return fmt.Sprintf("MatchMessage(%q)", m.message)
+}
+
+// MatchAttr returns a Matcher checking if the last attribute with the given key
+// equals value. Dotted keys ("user.id") are looked up in groups. Values are
+// normalized like slog does, so MatchAttr("count", 42) matches slog.Int("count", 42).
+//
+// Example usage:
+//
+//	r := slogmulti.Router().
+//	    AddMatchers(acmeHandler, slogmulti.MatchAttr("tenant", "acme")).
+//	    AddMatchers(globexHandler, slogmulti.MatchAttr("tenant", "globex")).
+//	    Handler()
+func MatchAttr(key string, value any) Matcher {
+	return attrMatcher{key: key, value: slog.AnyValue(value).Resolve()}
+}
+
+type attrMatcher struct {
+	key   string
+	value slog.Value
+}
+
+func (m attrMatcher) Match(_ context.Context, view *RecordView) bool {
+	value, ok := view.Value(m.key)
+	return ok && attrValueEquals(value.Resolve(), m.value)
+}
+
+func (m attrMatcher) String() string {
+	return fmt.Sprintf("MatchAttr(%q, %v)", m.key, m.value)
+}
+
+// attrValueEquals compares two values without allocating, except for KindAny
+// values, compared without panicking on non-comparable types.
+func attrValueEquals(a slog.Value, b slog.Value) bool {
+	if a.Kind() != b.Kind() {
+		return false
+	}
+	if a.Kind() == slog.KindAny {
+		return valueEquals(a.Any(), b.Any())
+	}
+
+	return a.Equal(b)
+}
+
+// valueEquals compares two values, without panicking on non-comparable types.
+func valueEquals(a any, b any) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+	if ta == nil {
+		return true
+	}
+	if !ta.Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+
+	return a == b
+}
+
+// matcherLabel returns a label for a matcher, for Explain and Inspect.
+func matcherLabel(matcher Matcher) string {
+	if stringer, ok := matcher.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	return funcLabel(matcher)
+}
@@ -0,0 +1,119 @@
+package slogmulti
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samber/slog-multi/slogmultitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchers(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	record := slog.NewRecord(time.Now(), slog.LevelError, "boom", 0)
+	record.AddAttrs(
+		slog.String("tenant", "acme"),
+		slog.Int("count", 42),
+		slog.Any("tags", []string{"a", "b"}),
+		slog.Group("user", slog.String("id", "u1")),
+	)
+	view := NewRecordView(record)
+	ctx := context.Background()
+
+	is.True(MatchLevel(slog.LevelWarn, slog.LevelError).Match(ctx, view))
+	is.False(MatchLevel(slog.LevelInfo).Match(ctx, view))
+	is.True(MatchMessage("boom").Match(ctx, view))
+	is.False(MatchMessage("boo").Match(ctx, view))
+	is.True(MatchAttr("tenant", "acme").Match(ctx, view))
+	is.False(MatchAttr("tenant", "globex").Match(ctx, view))
+	is.True(MatchAttr("count", 42).Match(ctx, view))
+	is.False(MatchAttr("count", "42").Match(ctx, view))
+	is.True(MatchAttr("user.id", "u1").Match(ctx, view))
+	is.False(MatchAttr("missing", "u1").Match(ctx, view))
+	// non-comparable values do not panic
+	is.True(MatchAttr("tags", []string{"a", "b"}).Match(ctx, view))
+	is.False(MatchAttr("tags", []string{"a"}).Match(ctx, view))
+
+	is.True(Predicate(LevelIs(slog.LevelError)).Match(ctx, view))
+	is.True(MatcherFunc(func(ctx context.Context, view *RecordView) bool { return view.Message() == "boom" }).Match(ctx, view))
+
+	is.Equal("MatchLevel(WARN, ERROR)", matcherLabel(MatchLevel(slog.LevelWarn, slog.LevelError)))
+	is.Equal(`MatchMessage("boom")`, matcherLabel(MatchMessage("boom")))
+	is.Equal(`MatchAttr("tenant", acme)`, matcherLabel(MatchAttr("tenant", "acme")))
+	is.Equal("LevelIs()", matcherLabel(Predicate(LevelIs(slog.LevelError))))
+}
+
+// sharedViewMatcher records the views it receives.
+type sharedViewMatcher struct {
+	views []*RecordView
+}
+
+func (m *sharedViewMatcher) Match(_ context.Context, view *RecordView) bool {
+	m.views = append(m.views, view)
+	return true
+}
+
+func TestRouterMatchersShareView(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	matcher := &sharedViewMatcher{}
+	buf := bytes.NewBufferString("")
+	var count atomic.Int64
+
+	logger := slog.New(
+		Router().
+			AddMatchers(slog.NewTextHandler(buf, &slog.HandlerOptions{ReplaceAttr: remoteTimeReplaceAttr}), matcher, MatchAttr("tenant", "acme")).
+			AddMatchers(NewHandleInlineHandler(func(ctx context.Context, groups []string, attrs []slog.Attr, record slog.Record) error {
+				count.Add(1)
+				return nil
+			}), matcher, MatchLevel(slog.LevelError)).
+			Add(&noopHandler{}, func(ctx context.Context, r slog.Record) bool {
+				// legacy predicates receive the accumulated attributes
+				return r.NumAttrs() == 2
+			}).
+			Handler(),
+	).With("tenant", "acme").WithGroup("g")
+
+	logger.Info("hello", "foo", "bar")
+	is.Equal("level=INFO msg=hello tenant=acme g.foo=bar\n", buf.String())
+	is.EqualValues(0, count.Load())
+
+	// every route received the same view
+	is.Len(matcher.views, 2)
+	is.Same(matcher.views[0], matcher.views[1])
+
+	explanation := Router().AddMatchers(&noopHandler{}, MatchLevel(slog.LevelError), Predicate(MessageIs("boom"))).
+		Explain(context.Background(), slog.NewRecord(time.Now(), slog.LevelError, "boom", 0))
+	is.Equal([]PredicateResult{{Label: "MatchLevel(ERROR)", Matched: true}, {Label: "MessageIs()", Matched: true}}, explanation.Routes[0].Predicates)
+}
+
+func TestRouterDuplicateKeys(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	// the same key, added with WithAttrs and by the record: predicates see both values
+	for name, handler := range map[string]func(sink slog.Handler) slog.Handler{
+		"router": func(sink slog.Handler) slog.Handler {
+			return Router().Add(sink, AttrValueIs("tenant", "a")).Handler()
+		},
+		"first match": func(sink slog.Handler) slog.Handler {
+			return Router().Add(sink, AttrValueIs("tenant", "a")).FirstMatch().Handler()
+		},
+	} {
+		sink := slogmultitest.NewRecordingHandler(nil)
+		logger := slog.New(handler(sink))
+
+		logger.With("tenant", "a").Info("x", "tenant", "b")
+		logger.With("tenant", "b").Info("x", "tenant", "a")
+		logger.With("tenant", "b").Info("x", "tenant", "c")
+
+		is.Equal(2, sink.Len(), name)
+	}
+}
@@ -0,0 +1,115 @@
+package slogmultitest
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+// AssertLogged asserts that a record with the given message has been captured.
+//
+//	slogmultitest.AssertLogged(t, recorder, "user created")
+func AssertLogged(t testing.TB, h *RecordingHandler, msg string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	if _, ok := h.FindByMessage(msg); !ok {
+		return fail(t, fmt.Sprintf("no record with message %q, got: %q", msg, h.Messages()), msgAndArgs...)
+	}
+
+	return true
+}
+
+// AssertNotLogged asserts that no record with the given message has been captured.
+func AssertNotLogged(t testing.TB, h *RecordingHandler, msg string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	if _, ok := h.FindByMessage(msg); ok {
+		return fail(t, fmt.Sprintf("unexpected record with message %q", msg), msgAndArgs...)
+	}
+
+	return true
+}
+
+// AssertCount asserts the number of captured records.
+func AssertCount(t testing.TB, h *RecordingHandler, expected int, msgAndArgs ...any) bool {
+	t.Helper()
+
+	if actual := h.Len(); actual != expected {
+		return fail(t, fmt.Sprintf("expected %d records, got %d: %q", expected, actual, h.Messages()), msgAndArgs...)
+	}
+
+	return true
+}
+
+// AssertLevelCount asserts the number of captured records at the given level.
+func AssertLevelCount(t testing.TB, h *RecordingHandler, level slog.Level, expected int, msgAndArgs ...any) bool {
+	t.Helper()
+
+	if actual := len(h.FilterByLevel(level)); actual != expected {
+		return fail(t, fmt.Sprintf("expected %d records at level %s, got %d", expected, level, actual), msgAndArgs...)
+	}
+
+	return true
+}
+
+// AssertAttr asserts that the attribute located at the given dotted path has
+// the expected value. Values are normalized like slog does, so 42 equals
+// slog.Int("count", 42).
+//
+//	record, _ := recorder.FindByMessage("request")
+//	slogmultitest.AssertAttr(t, record, "http.status", 200)
+func AssertAttr(t testing.TB, record Record, path string, expected any, msgAndArgs ...any) bool {
+	t.Helper()
+
+	actual, ok := record.AttrAt(path)
+	if !ok {
+		return fail(t, fmt.Sprintf("record %q has no attribute %q", record.Message, path), msgAndArgs...)
+	}
+
+	want := slog.AnyValue(expected).Resolve()
+	if actual.Kind() != want.Kind() || !reflect.DeepEqual(actual.Any(), want.Any()) {
+		return fail(t, fmt.Sprintf("record %q: attribute %q: expected %v (%s), got %v (%s)", record.Message, path, want, want.Kind(), actual, actual.Kind()), msgAndArgs...)
+	}
+
+	return true
+}
+
+// AssertNoAttr asserts that no attribute is located at the given dotted path.
+func AssertNoAttr(t testing.TB, record Record, path string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	if actual, ok := record.AttrAt(path); ok {
+		return fail(t, fmt.Sprintf("record %q: unexpected attribute %q=%v", record.Message, path, actual), msgAndArgs...)
+	}
+
+	return true
+}
+
+// fail reports a failure, with the optional message of the caller, like testify does.
+func fail(t testing.TB, failure string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	if message := messageFromMsgAndArgs(msgAndArgs...); message != "" {
+		t.Errorf("%s\n\tmessage: %s", failure, message)
+	} else {
+		t.Error(failure)
+	}
+
+	return false
+}
+
+func messageFromMsgAndArgs(msgAndArgs ...any) string {
+	if len(msgAndArgs) == 0 {
+		return ""
+	}
+
+	if format, ok := msgAndArgs[0].(string); ok {
+		if len(msgAndArgs) == 1 {
+			return format
+		}
+		return fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+
+	return fmt.Sprint(msgAndArgs...)
+}
@@ -0,0 +1,63 @@
+package slogmultitest
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Error(args ...any) {
+	t.failures = append(t.failures, fmt.Sprint(args...))
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	recorder := NewRecordingHandler(nil)
+	logger := slog.New(recorder)
+	logger.WithGroup("http").Info("request", "status", 200, "path", "/users")
+	logger.Error("boom")
+
+	record, _ := recorder.FindByMessage("request")
+
+	// passing assertions
+	ft := &fakeT{}
+	is.True(AssertLogged(ft, recorder, "request"))
+	is.True(AssertNotLogged(ft, recorder, "missing"))
+	is.True(AssertCount(ft, recorder, 2))
+	is.True(AssertLevelCount(ft, recorder, slog.LevelError, 1))
+	is.True(AssertAttr(ft, record, "http.status", 200))
+	is.True(AssertAttr(ft, record, "http.path", "/users"))
+	is.True(AssertNoAttr(ft, record, "http.missing"))
+	is.Empty(ft.failures)
+
+	// failing assertions
+	is.False(AssertLogged(ft, recorder, "missing"))
+	is.False(AssertNotLogged(ft, recorder, "boom", "boom should not be logged"))
+	is.False(AssertCount(ft, recorder, 3))
+	is.False(AssertLevelCount(ft, recorder, slog.LevelWarn, 1))
+	is.False(AssertAttr(ft, record, "http.status", "200"))
+	is.False(AssertAttr(ft, record, "http.missing", 1, "status of request"))
+	is.False(AssertNoAttr(ft, record, "http.status"))
+	is.Len(ft.failures, 7)
+
+	is.Equal(`no record with message "missing", got: ["request" "boom"]`, ft.failures[0])
+	is.Equal("unexpected record with message \"boom\"\n\tmessage: boom should not be logged", ft.failures[1])
+	is.Equal(`record "request": attribute "http.status": expected 200 (String), got 200 (Int64)`, ft.failures[4])
+	is.Equal("record \"request\" has no attribute \"http.missing\"\n\tmessage: status of request", ft.failures[5])
+}
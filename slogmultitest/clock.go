@@ -0,0 +1,126 @@
+package slogmultitest
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewFakeClock returns a clock frozen at start, for time-based middlewares
+// such as slogmulti.Aggregate. Time only moves with Advance, which runs the
+// timers falling due synchronously.
+//
+// Example usage:
+//
+//	clock := slogmultitest.NewFakeClock(time.Now())
+//	logger := slog.New(
+//	    slogmulti.
+//	        Pipe(slogmulti.Aggregate(slogmulti.AggregateOption{Window: time.Minute, Clock: clock})).
+//	        Handler(recorder),
+//	)
+//
+//	logger.Info("cache hit")
+//	clock.Advance(time.Minute) // emits the summaries
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// FakeClock is a manually advanced clock. It implements slogmulti.Clock.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	fn      func()
+	stopped bool
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// AfterFunc registers f to be called when the clock is advanced by d or more.
+// The returned function stops the timer, and reports whether it was pending.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &fakeTimer{at: c.now.Add(d), fn: f}
+	c.timers = append(c.timers, timer)
+
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		pending := !timer.stopped
+		timer.stopped = true
+		return pending
+	}
+}
+
+// Advance moves the clock forward by d, and calls the timers falling due, in
+// chronological order. The clock is set to the time of each timer before it
+// is called, so timers registered by a callback fire too if they fall due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		timer := c.nextTimer(end)
+		if timer == nil {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+
+		timer.stopped = true
+		if timer.at.After(c.now) {
+			c.now = timer.at
+		}
+		c.mu.Unlock()
+
+		timer.fn()
+	}
+}
+
+// Timers returns the number of pending timers.
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timers = pendingTimers(c.timers)
+	return len(c.timers)
+}
+
+// nextTimer returns the earliest pending timer due at end, or nil.
+func (c *FakeClock) nextTimer(end time.Time) *fakeTimer {
+	c.timers = pendingTimers(c.timers)
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].at.Before(c.timers[j].at)
+	})
+
+	if len(c.timers) == 0 || c.timers[0].at.After(end) {
+		return nil
+	}
+
+	return c.timers[0]
+}
+
+func pendingTimers(timers []*fakeTimer) []*fakeTimer {
+	output := timers[:0]
+	for _, timer := range timers {
+		if !timer.stopped {
+			output = append(output, timer)
+		}
+	}
+	return output
+}
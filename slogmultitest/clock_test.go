@@ -0,0 +1,45 @@
+package slogmultitest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	is.Equal(start, clock.Now())
+
+	fired := []string{}
+	clock.AfterFunc(2*time.Second, func() {
+		fired = append(fired, "2s@"+clock.Now().Sub(start).String())
+	})
+	clock.AfterFunc(time.Second, func() {
+		fired = append(fired, "1s@"+clock.Now().Sub(start).String())
+		// timers registered by a callback fire when they fall due
+		clock.AfterFunc(500*time.Millisecond, func() {
+			fired = append(fired, "1.5s@"+clock.Now().Sub(start).String())
+		})
+	})
+	stop := clock.AfterFunc(time.Second, func() {
+		fired = append(fired, "stopped")
+	})
+	is.Equal(3, clock.Timers())
+
+	is.True(stop())
+	is.False(stop())
+	is.Equal(2, clock.Timers())
+
+	clock.Advance(999 * time.Millisecond)
+	is.Empty(fired)
+
+	clock.Advance(5 * time.Second)
+	is.Equal([]string{"1s@1s", "1.5s@1.5s", "2s@2s"}, fired)
+	is.Equal(start.Add(5999*time.Millisecond), clock.Now())
+	is.Equal(0, clock.Timers())
+}
@@ -0,0 +1,64 @@
+package slogmultitest
+
+import (
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+)
+
+// RunConformance checks that a handler built around a sink keeps the
+// slog.Handler contract, using testing/slogtest: attributes, groups, empty
+// groups, inlined groups, zero time and slog.LogValuer resolution.
+//
+// wrap receives a RecordingHandler as sink and returns the handler under test
+// (a middleware, a fanout, a router...). Each check logs a single record
+// through the returned handler and inspects the last record received by the
+// sink, so the handler must forward records to the sink unchanged.
+//
+// Example usage:
+//
+//	func TestMyMiddlewareConformance(t *testing.T) {
+//	    slogmultitest.RunConformance(t, func(sink slog.Handler) slog.Handler {
+//	        return slogmulti.Pipe(myMiddleware).Handler(sink)
+//	    })
+//	}
+//
+// Args:
+//
+//	t: the test
+//	wrap: builds the handler under test around the sink
+func RunConformance(t *testing.T, wrap func(sink slog.Handler) slog.Handler) {
+	t.Helper()
+
+	var recorder *RecordingHandler
+
+	slogtest.Run(
+		t,
+		func(t *testing.T) slog.Handler {
+			recorder = NewRecordingHandler(nil)
+			return wrap(recorder)
+		},
+		func(t *testing.T) map[string]any {
+			record, ok := recorder.Last()
+			if !ok {
+				t.Fatal("no record received by the sink")
+			}
+			return ConformanceResult(record)
+		},
+	)
+}
+
+// ConformanceResult converts a record to the map expected by testing/slogtest:
+// attributes and groups as nested maps, plus the built-in time (when not zero),
+// level and message keys.
+func ConformanceResult(record Record) map[string]any {
+	result := record.Map()
+
+	if !record.Time.IsZero() {
+		result[slog.TimeKey] = record.Time
+	}
+	result[slog.LevelKey] = record.Level
+	result[slog.MessageKey] = record.Message
+
+	return result
+}
@@ -0,0 +1,22 @@
+package slogmultitest
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestRecordingHandlerConformance(t *testing.T) {
+	t.Parallel()
+
+	RunConformance(t, func(sink slog.Handler) slog.Handler {
+		return sink
+	})
+}
+
+func TestFaultHandlerConformance(t *testing.T) {
+	t.Parallel()
+
+	RunConformance(t, func(sink slog.Handler) slog.Handler {
+		return NewFailingHandler(nil, Always(), sink)
+	})
+}
@@ -0,0 +1,187 @@
+package slogmultitest
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Schedule decides whether the fault of a FaultHandler is injected on a call.
+// Calls are numbered from 1.
+type Schedule func(call int64) bool
+
+// Always injects the fault on every call.
+func Always() Schedule {
+	return func(int64) bool { return true }
+}
+
+// Never never injects the fault.
+func Never() Schedule {
+	return func(int64) bool { return false }
+}
+
+// Every injects the fault on calls n, 2n, 3n...
+func Every(n int64) Schedule {
+	if n <= 0 {
+		panic("slog-multi: n must be positive")
+	}
+
+	return func(call int64) bool { return call%n == 0 }
+}
+
+// FirstN injects the fault on the n first calls, then recovers.
+func FirstN(n int64) Schedule {
+	return func(call int64) bool { return call <= n }
+}
+
+// AfterN injects the fault on every call after the n first ones.
+func AfterN(n int64) Schedule {
+	return func(call int64) bool { return call > n }
+}
+
+// Calls injects the fault on the given calls only.
+func Calls(calls ...int64) Schedule {
+	return func(call int64) bool {
+		for _, c := range calls {
+			if c == call {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NewFailingHandler returns a handler returning err on scheduled calls.
+// Other calls are forwarded to next. When next is nil, records are discarded.
+//
+// Example usage:
+//
+//	flaky := slogmultitest.NewFailingHandler(errors.New("unavailable"), slogmultitest.Every(3), recorder)
+//	logger := slog.New(slogmulti.Failover()(flaky, backup))
+func NewFailingHandler(err error, schedule Schedule, next slog.Handler) *FaultHandler {
+	return newFaultHandler(schedule, next, func(context.Context) error {
+		return err
+	})
+}
+
+// NewPanickingHandler returns a handler panicking with value on scheduled calls.
+// Other calls are forwarded to next. When next is nil, records are discarded.
+func NewPanickingHandler(value any, schedule Schedule, next slog.Handler) *FaultHandler {
+	return newFaultHandler(schedule, next, func(context.Context) error {
+		panic(value)
+	})
+}
+
+// NewSlowHandler returns a handler waiting for latency on scheduled calls,
+// before forwarding the record to next. The wait is interrupted, and the
+// context error returned, when the context is canceled. When next is nil,
+// records are discarded.
+func NewSlowHandler(latency time.Duration, schedule Schedule, next slog.Handler) *FaultHandler {
+	return newFaultHandler(schedule, next, func(ctx context.Context) error {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+func newFaultHandler(schedule Schedule, next slog.Handler, fault func(context.Context) error) *FaultHandler {
+	if schedule == nil {
+		panic("slog-multi: schedule is required")
+	}
+
+	return &FaultHandler{
+		next:     next,
+		schedule: schedule,
+		fault:    fault,
+		counters: &faultCounters{},
+	}
+}
+
+type faultCounters struct {
+	calls  atomic.Int64
+	faults atomic.Int64
+}
+
+// Ensure FaultHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*FaultHandler)(nil)
+
+// FaultHandler injects a fault (error, panic or latency) on scheduled calls to
+// Handle. Handlers derived with WithAttrs and WithGroup share the same call counter.
+type FaultHandler struct {
+	next     slog.Handler
+	schedule Schedule
+	fault    func(context.Context) error
+	counters *faultCounters
+}
+
+// Enabled forwards the call to next. Every level is enabled when next is nil.
+// This method implements the slog.Handler interface requirement.
+func (h *FaultHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next == nil || h.next.Enabled(ctx, level)
+}
+
+// Handle injects the fault when scheduled, then forwards the record to next.
+// This method implements the slog.Handler interface requirement.
+func (h *FaultHandler) Handle(ctx context.Context, r slog.Record) error {
+	call := h.counters.calls.Add(1)
+	if h.schedule(call) {
+		h.counters.faults.Add(1)
+		if err := h.fault(ctx); err != nil {
+			return err
+		}
+	}
+
+	if h.next == nil {
+		return nil
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs forwards the attributes to next.
+// This method implements the slog.Handler interface requirement.
+func (h *FaultHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if h.next == nil {
+		return h
+	}
+
+	return &FaultHandler{
+		next:     h.next.WithAttrs(attrs),
+		schedule: h.schedule,
+		fault:    h.fault,
+		counters: h.counters,
+	}
+}
+
+// WithGroup forwards the group to next.
+// This method implements the slog.Handler interface requirement.
+func (h *FaultHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" || h.next == nil {
+		return h
+	}
+
+	return &FaultHandler{
+		next:     h.next.WithGroup(name),
+		schedule: h.schedule,
+		fault:    h.fault,
+		counters: h.counters,
+	}
+}
+
+// Calls returns the number of calls to Handle.
+func (h *FaultHandler) Calls() int64 {
+	return h.counters.calls.Load()
+}
+
+// Faults returns the number of injected faults.
+func (h *FaultHandler) Faults() int64 {
+	return h.counters.faults.Load()
+}
@@ -0,0 +1,101 @@
+package slogmultitest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedules(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	run := func(schedule Schedule) []int64 {
+		calls := []int64{}
+		for call := int64(1); call <= 6; call++ {
+			if schedule(call) {
+				calls = append(calls, call)
+			}
+		}
+		return calls
+	}
+
+	is.Equal([]int64{1, 2, 3, 4, 5, 6}, run(Always()))
+	is.Equal([]int64{}, run(Never()))
+	is.Equal([]int64{3, 6}, run(Every(3)))
+	is.Equal([]int64{1, 2}, run(FirstN(2)))
+	is.Equal([]int64{5, 6}, run(AfterN(4)))
+	is.Equal([]int64{2, 5}, run(Calls(2, 5)))
+	is.Panics(func() { Every(0) })
+}
+
+func TestFailingHandler(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	err := errors.New("unavailable")
+	recorder := NewRecordingHandler(nil)
+	handler := NewFailingHandler(err, Every(2), recorder)
+
+	derived := handler.WithAttrs([]slog.Attr{slog.String("env", "prod")}).WithGroup("g")
+	for i := 0; i < 4; i++ {
+		e := derived.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+		if i%2 == 1 {
+			is.ErrorIs(e, err)
+		} else {
+			is.NoError(e)
+		}
+	}
+
+	is.EqualValues(4, handler.Calls())
+	is.EqualValues(2, handler.Faults())
+	is.Equal(2, recorder.Len())
+
+	record, _ := recorder.Last()
+	AssertAttr(t, record, "env", "prod")
+
+	// without next, records are discarded
+	discard := NewFailingHandler(err, Never(), nil)
+	is.True(discard.Enabled(context.Background(), slog.LevelDebug))
+	is.NoError(discard.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)))
+	is.Same(discard, discard.WithAttrs([]slog.Attr{slog.Int("a", 1)}))
+	is.Same(discard, discard.WithGroup("g"))
+}
+
+func TestPanickingHandler(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	handler := NewPanickingHandler("boom", FirstN(1), nil)
+
+	is.PanicsWithValue("boom", func() {
+		_ = handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+	})
+	is.NotPanics(func() {
+		_ = handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+	})
+	is.EqualValues(1, handler.Faults())
+}
+
+func TestSlowHandler(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	recorder := NewRecordingHandler(nil)
+	handler := NewSlowHandler(20*time.Millisecond, Always(), recorder)
+
+	start := time.Now()
+	is.NoError(handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)))
+	is.GreaterOrEqual(time.Since(start), 20*time.Millisecond)
+	is.Equal(1, recorder.Len())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	handler = NewSlowHandler(time.Hour, Always(), recorder)
+	is.ErrorIs(handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)), context.Canceled)
+	is.Equal(1, recorder.Len())
+}
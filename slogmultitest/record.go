@@ -0,0 +1,45 @@
+package slogmultitest
+
+import (
+	"log/slog"
+	"time"
+
+	slogcommon "github.com/samber/slog-common"
+	"github.com/samber/slog-multi/internal/slogattr"
+)
+
+// Record is a record captured by a RecordingHandler.
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	PC      uintptr
+
+	// Groups are the groups opened with WithGroup on the handler that received the record.
+	Groups []string
+	// Attrs are the attributes accumulated with WithAttrs merged with the record
+	// attributes, nested under their groups, with slog.LogValuer values resolved.
+	Attrs []slog.Attr
+}
+
+// AttrAt returns the value of the last attribute located at the given dotted
+// path ("http.status").
+func (r Record) AttrAt(path string) (slog.Value, bool) {
+	attr, ok := slogattr.FindByPath(r.Attrs, slogattr.SplitPath(path))
+	if !ok {
+		return slog.Value{}, false
+	}
+
+	return attr.Value, true
+}
+
+// HasAttr reports whether an attribute is located at the given dotted path.
+func (r Record) HasAttr(path string) bool {
+	_, ok := r.AttrAt(path)
+	return ok
+}
+
+// Map converts the attributes to nested maps: groups are map[string]any.
+func (r Record) Map() map[string]any {
+	return slogcommon.AttrsToMap(r.Attrs...)
+}
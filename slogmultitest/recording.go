@@ -0,0 +1,218 @@
+// Package slogmultitest provides in-memory handlers, assertions and
+// fault-injecting handlers to test code built on log/slog and slog-multi.
+package slogmultitest
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	slogcommon "github.com/samber/slog-common"
+	"github.com/samber/slog-multi/internal/slogattr"
+)
+
+// NewRecordingHandler returns a handler capturing records in memory, for tests.
+// Records are captured with their groups and their resolved attributes.
+// Handlers derived with WithAttrs and WithGroup share the same records.
+//
+// Example usage:
+//
+//	recorder := slogmultitest.NewRecordingHandler(nil)
+//	logger := slog.New(slogmulti.Fanout(recorder, otherHandler))
+//
+//	logger.With("env", "prod").Error("boom", "status", 500)
+//
+//	record, ok := recorder.FindByMessage("boom")
+//	status, _ := record.AttrAt("status")
+//
+// Args:
+//
+//	level: the minimum level of captured records. nil captures every level.
+//
+// Returns:
+//
+//	A thread-safe recording handler
+func NewRecordingHandler(level slog.Leveler) *RecordingHandler {
+	return &RecordingHandler{
+		store:  &recordStore{},
+		level:  level,
+		groups: []string{},
+		attrs:  []slog.Attr{},
+	}
+}
+
+type recordStore struct {
+	mu      sync.RWMutex
+	records []Record
+}
+
+// Ensure RecordingHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*RecordingHandler)(nil)
+
+// RecordingHandler is an in-memory slog.Handler for tests. See NewRecordingHandler.
+type RecordingHandler struct {
+	store  *recordStore
+	level  slog.Leveler
+	groups []string
+	// attrs are resolved and nested under their groups
+	attrs []slog.Attr
+}
+
+// Enabled reports whether the level reaches the minimum level of the handler.
+// This method implements the slog.Handler interface requirement.
+func (h *RecordingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.level == nil || level >= h.level.Level()
+}
+
+// Handle captures the record.
+// This method implements the slog.Handler interface requirement.
+func (h *RecordingHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := h.attrs
+	if r.NumAttrs() > 0 {
+		recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
+		r.Attrs(func(attr slog.Attr) bool {
+			recordAttrs = append(recordAttrs, attr)
+			return true
+		})
+
+		recordAttrs = slogattr.Resolve(recordAttrs)
+		if len(recordAttrs) > 0 {
+			attrs = slogcommon.AppendAttrsToGroup(h.groups, h.attrs, recordAttrs...)
+		}
+	}
+
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+
+	h.store.records = append(h.store.records, Record{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		PC:      r.PC,
+		Groups:  h.groups,
+		Attrs:   attrs,
+	})
+
+	return nil
+}
+
+// WithAttrs returns a handler sharing the records of h, with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *RecordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs = slogattr.Resolve(attrs)
+	if len(attrs) == 0 {
+		return h
+	}
+
+	return &RecordingHandler{
+		store:  h.store,
+		level:  h.level,
+		groups: h.groups,
+		attrs:  slogcommon.AppendAttrsToGroup(h.groups, h.attrs, attrs...),
+	}
+}
+
+// WithGroup returns a handler sharing the records of h, with an additional group.
+// This method implements the slog.Handler interface requirement.
+func (h *RecordingHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, 0, len(h.groups)+1)
+	groups = append(groups, h.groups...)
+	groups = append(groups, name)
+
+	return &RecordingHandler{
+		store:  h.store,
+		level:  h.level,
+		groups: groups,
+		attrs:  h.attrs,
+	}
+}
+
+// Records returns a copy of the captured records, in order.
+func (h *RecordingHandler) Records() []Record {
+	h.store.mu.RLock()
+	defer h.store.mu.RUnlock()
+
+	records := make([]Record, len(h.store.records))
+	copy(records, h.store.records)
+	return records
+}
+
+// Len returns the number of captured records.
+func (h *RecordingHandler) Len() int {
+	h.store.mu.RLock()
+	defer h.store.mu.RUnlock()
+
+	return len(h.store.records)
+}
+
+// Reset removes the captured records.
+func (h *RecordingHandler) Reset() {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+
+	h.store.records = nil
+}
+
+// Last returns the last captured record.
+func (h *RecordingHandler) Last() (Record, bool) {
+	h.store.mu.RLock()
+	defer h.store.mu.RUnlock()
+
+	if len(h.store.records) == 0 {
+		return Record{}, false
+	}
+
+	return h.store.records[len(h.store.records)-1], true
+}
+
+// Messages returns the messages of the captured records, in order.
+func (h *RecordingHandler) Messages() []string {
+	records := h.Records()
+	messages := make([]string, 0, len(records))
+	for _, record := range records {
+		messages = append(messages, record.Message)
+	}
+	return messages
+}
+
+// FindByMessage returns the first captured record with the given message.
+func (h *RecordingHandler) FindByMessage(msg string) (Record, bool) {
+	h.store.mu.RLock()
+	defer h.store.mu.RUnlock()
+
+	for _, record := range h.store.records {
+		if record.Message == msg {
+			return record, true
+		}
+	}
+
+	return Record{}, false
+}
+
+// FilterByLevel returns the captured records having one of the given levels.
+func (h *RecordingHandler) FilterByLevel(levels ...slog.Level) []Record {
+	return h.Filter(func(record Record) bool {
+		for _, level := range levels {
+			if record.Level == level {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Filter returns the captured records matching the predicate.
+func (h *RecordingHandler) Filter(predicate func(record Record) bool) []Record {
+	output := []Record{}
+	for _, record := range h.Records() {
+		if predicate(record) {
+			output = append(output, record)
+		}
+	}
+	return output
+}
@@ -0,0 +1,113 @@
+package slogmultitest
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	id string
+}
+
+func (u user) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", u.id))
+}
+
+func TestRecordingHandler(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	recorder := NewRecordingHandler(nil)
+	logger := slog.New(recorder)
+
+	logger.With("env", "prod").WithGroup("http").With("method", "GET").Info("request", "status", 200, "user", user{id: "42"})
+	logger.Warn("slow", slog.Group("empty"))
+	logger.Error("boom")
+
+	is.Equal(3, recorder.Len())
+	is.Equal([]string{"request", "slow", "boom"}, recorder.Messages())
+
+	record, ok := recorder.FindByMessage("request")
+	is.True(ok)
+	is.Equal(slog.LevelInfo, record.Level)
+	is.Equal([]string{"http"}, record.Groups)
+	is.Equal(
+		map[string]any{
+			"env": "prod",
+			"http": map[string]any{
+				"method": "GET",
+				"status": int64(200),
+				"user":   map[string]any{"id": "42"},
+			},
+		},
+		record.Map(),
+	)
+
+	value, ok := record.AttrAt("http.user.id")
+	is.True(ok)
+	is.Equal("42", value.String())
+	is.True(record.HasAttr("env"))
+	is.False(record.HasAttr("http.missing"))
+
+	slow, ok := recorder.FindByMessage("slow")
+	is.True(ok)
+	is.Empty(slow.Attrs)
+
+	_, ok = recorder.FindByMessage("missing")
+	is.False(ok)
+
+	is.Len(recorder.FilterByLevel(slog.LevelWarn, slog.LevelError), 2)
+	is.Len(recorder.Filter(func(r Record) bool { return r.HasAttr("env") }), 1)
+
+	last, ok := recorder.Last()
+	is.True(ok)
+	is.Equal("boom", last.Message)
+
+	recorder.Reset()
+	is.Equal(0, recorder.Len())
+	_, ok = recorder.Last()
+	is.False(ok)
+}
+
+func TestRecordingHandlerLevel(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	recorder := NewRecordingHandler(slog.LevelWarn)
+	is.False(recorder.Enabled(context.Background(), slog.LevelInfo))
+	is.True(recorder.Enabled(context.Background(), slog.LevelWarn))
+
+	slog.New(recorder).Info("dropped")
+	is.Equal(0, recorder.Len())
+
+	is.Same(recorder, recorder.WithGroup(""))
+	is.Same(recorder, recorder.WithAttrs(nil))
+}
+
+func TestRecordingHandlerConcurrency(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	recorder := NewRecordingHandler(nil)
+	logger := slog.New(recorder)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l := logger.With("goroutine", i)
+			for j := 0; j < 100; j++ {
+				l.Info("hello", "j", j)
+				_ = recorder.Len()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	is.Equal(1000, recorder.Len())
+}
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/samber/slog-multi/slogmultitest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -766,3 +767,26 @@ func TestAdversarialHighContention(t *testing.T) {
 	expected := int64(goroutines * logsPerRoutine * 2)
 	assert.Equal(t, expected, sink.handleCount.Load())
 }
+
+func TestAdversarialFailoverFaultInjection(t *testing.T) {
+	t.Parallel()
+
+	backup := slogmultitest.NewRecordingHandler(nil)
+	primary := slogmultitest.NewRecordingHandler(nil)
+	flaky := slogmultitest.NewFailingHandler(errors.New("unavailable"), slogmultitest.Every(3), primary)
+	panicky := slogmultitest.NewPanickingHandler("boom", slogmultitest.Calls(2), flaky)
+
+	logger := slog.New(Failover()(panicky, backup)).With("env", "prod")
+	for i := 1; i <= 6; i++ {
+		logger.Info(fmt.Sprintf("msg-%d", i))
+	}
+
+	// msg-2 panics, then the flaky handler fails on its 3rd call (msg-4)
+	assert.Equal(t, []string{"msg-1", "msg-3", "msg-5", "msg-6"}, primary.Messages())
+	assert.Equal(t, []string{"msg-2", "msg-4"}, backup.Messages())
+	assert.EqualValues(t, 1, panicky.Faults())
+	assert.EqualValues(t, 1, flaky.Faults())
+
+	record, _ := backup.FindByMessage("msg-2")
+	slogmultitest.AssertAttr(t, record, "env", "prod")
+}
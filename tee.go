@@ -0,0 +1,225 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// TeeBranch is a named sub-pipeline of a Tee: its own middlewares and its own sink.
+type TeeBranch struct {
+	name    string
+	handler slog.Handler
+}
+
+// Branch creates a named sub-pipeline for Tee and Branches. Middlewares are
+// applied in order, like in Pipe, before forwarding records to handler.
+//
+// Args:
+//
+//	name: The name of the branch, used in errors and by Inspect
+//	handler: The sink of the branch
+//	middlewares: The middlewares of the branch
+//
+// Returns:
+//
+//	A branch
+func Branch(name string, handler slog.Handler, middlewares ...Middleware) TeeBranch {
+	if handler == nil {
+		panic("slog-multi: handler is required")
+	}
+
+	return TeeBranch{
+		name:    name,
+		handler: Pipe(middlewares...).Handler(handler),
+	}
+}
+
+// Ensure TeeHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*TeeHandler)(nil)
+
+// TeeHandler splits a pipeline into named sub-pipelines. See Tee and Branches.
+type TeeHandler struct {
+	// next is the main line of the pipeline, nil for Branches
+	next     slog.Handler
+	branches []TeeBranch
+}
+
+// Tee creates a middleware splitting a pipeline: records are forwarded to the
+// next handler, which is the main line, and to every branch. Each branch
+// receives its own clone of the record, so that the mutations made by the
+// middlewares of a branch are not seen by the others.
+//
+// Example usage:
+//
+//	logger := slog.New(
+//	    slogmulti.
+//	        Pipe(slogmulti.ContextAttrs("")).
+//	        Pipe(slogmulti.Tee(
+//	            slogmulti.Branch("datadog", datadogHandler, redactPII),
+//	            slogmulti.Branch("archive", archiveHandler, sampling),
+//	        )).
+//	        Handler(stdoutHandler),
+//	)
+//
+// Args:
+//
+//	branches: The sub-pipelines receiving a copy of each record
+//
+// Returns:
+//
+//	A middleware forwarding records to the next handler and to every branch
+func Tee(branches ...TeeBranch) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		if next == nil {
+			panic("slog-multi: next is required")
+		}
+
+		return &TeeHandler{
+			next:     next,
+			branches: slices.Clone(branches),
+		}
+	}
+}
+
+// Branches creates a handler splitting a pipeline into branches, without main
+// line. It is meant to be the final handler of a Pipe.
+//
+// Example usage:
+//
+//	logger := slog.New(
+//	    slogmulti.
+//	        Pipe(slogmulti.ContextAttrs("")).
+//	        Handler(slogmulti.Branches(
+//	            slogmulti.Branch("datadog", datadogHandler, redactPII),
+//	            slogmulti.Branch("archive", archiveHandler, sampling),
+//	        )),
+//	)
+func Branches(branches ...TeeBranch) slog.Handler {
+	return &TeeHandler{
+		branches: slices.Clone(branches),
+	}
+}
+
+// Enabled checks if the main line or any branch is enabled for the given log level.
+// This method implements the slog.Handler interface requirement.
+func (h *TeeHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	if h.next != nil && h.next.Enabled(ctx, l) {
+		return true
+	}
+
+	for i := range h.branches {
+		if h.branches[i].handler.Enabled(ctx, l) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handle forwards a clone of the record to every enabled branch, then the
+// record to the main line. Branch errors are prefixed with the branch name.
+// This method implements the slog.Handler interface requirement.
+func (h *TeeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for i := range h.branches {
+		branch := h.branches[i]
+		if !branch.handler.Enabled(ctx, r.Level) {
+			continue
+		}
+
+		err := try(func() error {
+			return branch.handler.Handle(ctx, r.Clone())
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("slog-multi: branch %q: %w", branch.name, err))
+		}
+	}
+
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		if err := try(func() error { return h.next.Handle(ctx, r) }); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs forwards the attributes to the main line and to every branch.
+// This method implements the slog.Handler interface requirement.
+func (h *TeeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(
+		func(handler slog.Handler) slog.Handler { return handler.WithAttrs(slices.Clone(attrs)) },
+	)
+}
+
+// WithGroup forwards the group to the main line and to every branch.
+// This method implements the slog.Handler interface requirement.
+func (h *TeeHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	return h.derive(
+		func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) },
+	)
+}
+
+func (h *TeeHandler) derive(fn func(slog.Handler) slog.Handler) *TeeHandler {
+	branches := make([]TeeBranch, 0, len(h.branches))
+	for _, branch := range h.branches {
+		branches = append(branches, TeeBranch{name: branch.name, handler: fn(branch.handler)})
+	}
+
+	var next slog.Handler
+	if h.next != nil {
+		next = fn(h.next)
+	}
+
+	return &TeeHandler{
+		next:     next,
+		branches: branches,
+	}
+}
+
+// Flush flushes every branch, then the main line.
+// This method implements the Flusher interface.
+func (h *TeeHandler) Flush(ctx context.Context) error {
+	return flushHandlers(ctx, h.handlers()...)
+}
+
+// Close closes every branch, then the main line.
+// This method implements the Closer interface.
+func (h *TeeHandler) Close(ctx context.Context) error {
+	return closeHandlers(ctx, h.handlers()...)
+}
+
+func (h *TeeHandler) handlers() []slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.branches)+1)
+	for _, branch := range h.branches {
+		handlers = append(handlers, branch.handler)
+	}
+	if h.next != nil {
+		handlers = append(handlers, h.next)
+	}
+	return handlers
+}
+
+// Inspect describes the branches and the main line.
+// This method implements the Inspector interface.
+func (h *TeeHandler) Inspect() HandlerNode {
+	node := HandlerNode{Type: "Tee"}
+	for _, branch := range h.branches {
+		child := inspectNode("Branch", nil, nil, branch.handler)
+		child.Name = branch.name
+		node.Children = append(node.Children, child)
+	}
+	if h.next != nil {
+		node.Children = append(node.Children, Inspect(h.next))
+	}
+
+	return node
+}
@@ -0,0 +1,93 @@
+package slogmulti
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/samber/slog-multi/slogmultitest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	main := slogmultitest.NewRecordingHandler(nil)
+	redacted := slogmultitest.NewRecordingHandler(nil)
+	errorsOnly := slogmultitest.NewRecordingHandler(slog.LevelError)
+
+	redactCalls := 0
+	logger := slog.New(
+		Pipe(tagMiddleware("common")).
+			Pipe(Tee(
+				Branch("redacted", redacted, redactMiddleware(&redactCalls)),
+				Branch("errors", errorsOnly, tagMiddleware("errors")),
+			)).
+			Handler(main),
+	).With("env", "prod").WithGroup("g")
+
+	logger.Info("hello")
+	logger.Error("boom")
+
+	is.Equal([]string{"hello", "boom"}, main.Messages())
+	is.Equal([]string{"hello", "boom"}, redacted.Messages())
+	is.Equal([]string{"boom"}, errorsOnly.Messages())
+	is.Equal(2, redactCalls)
+
+	// mutations made in a branch are not seen by the others
+	record, _ := main.Last()
+	slogmultitest.AssertAttr(t, record, "g.path", "common")
+	slogmultitest.AssertAttr(t, record, "env", "prod")
+	record, _ = redacted.Last()
+	slogmultitest.AssertAttr(t, record, "g.path", "***")
+	record, _ = errorsOnly.Last()
+	slogmultitest.AssertAttr(t, record, "g.path", "common>errors")
+}
+
+func TestBranches(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	err := errors.New("unavailable")
+	ok := slogmultitest.NewRecordingHandler(nil)
+	failing := slogmultitest.NewFailingHandler(err, slogmultitest.Always(), nil)
+	panicking := slogmultitest.NewPanickingHandler("boom", slogmultitest.Always(), nil)
+
+	handler := Branches(
+		Branch("failing", failing),
+		Branch("panicking", panicking),
+		Branch("ok", ok),
+	)
+
+	result := handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+	is.ErrorIs(result, err)
+	is.ErrorContains(result, `slog-multi: branch "failing": unavailable`)
+	is.ErrorContains(result, `slog-multi: branch "panicking": `)
+	is.Equal(1, ok.Len())
+
+	is.False(Branches().Enabled(context.Background(), slog.LevelError))
+	is.Panics(func() { Branch("nil", nil) })
+}
+
+func TestTeeLifecycleAndInspect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	branch := &lifecycleHandler{}
+	main := &lifecycleHandler{}
+	handler := Tee(Branch("archive", branch, Transform()))(main).WithGroup("g")
+
+	is.NoError(Shutdown(context.Background(), handler))
+	is.EqualValues(1, branch.closeCount.Load())
+	is.EqualValues(1, main.closeCount.Load())
+
+	is.Equal(`Tee
+├── Branch name="archive"
+│   └── Transform groups=g
+│       └── *slogmulti.lifecycleHandler
+└── *slogmulti.lifecycleHandler
+`, Describe(handler))
+}
@@ -0,0 +1,298 @@
+package slogmulti
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+)
+
+// ErrInvalidTraceparent is returned when parsing a malformed W3C traceparent header.
+var ErrInvalidTraceparent = errors.New("slog-multi: invalid traceparent")
+
+// SpanContext is a minimal representation of a distributed tracing span, compatible
+// with the W3C Trace Context specification. It allows correlating logs with traces
+// without depending on a tracing SDK.
+type SpanContext struct {
+	TraceID    [16]byte
+	SpanID     [8]byte
+	TraceFlags byte
+}
+
+// IsValid returns true when both the trace id and the span id are non-zero.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// IsSampled returns true when the "sampled" trace flag is set.
+func (sc SpanContext) IsSampled() bool {
+	return sc.TraceFlags&0x01 == 0x01
+}
+
+// TraceIDString returns the hex encoded trace id.
+func (sc SpanContext) TraceIDString() string {
+	return hex.EncodeToString(sc.TraceID[:])
+}
+
+// SpanIDString returns the hex encoded span id.
+func (sc SpanContext) SpanIDString() string {
+	return hex.EncodeToString(sc.SpanID[:])
+}
+
+// TraceFlagsString returns the hex encoded trace flags.
+func (sc SpanContext) TraceFlagsString() string {
+	return hex.EncodeToString([]byte{sc.TraceFlags})
+}
+
+// Traceparent returns the W3C traceparent representation of the span context.
+func (sc SpanContext) Traceparent() string {
+	return "00-" + sc.TraceIDString() + "-" + sc.SpanIDString() + "-" + sc.TraceFlagsString()
+}
+
+// ParseTraceparent parses a W3C traceparent header value, such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+//
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+func ParseTraceparent(traceparent string) (SpanContext, error) {
+	// version(2) - trace-id(32) - parent-id(16) - trace-flags(2)
+	const size = 55
+
+	if len(traceparent) < size ||
+		traceparent[2] != '-' || traceparent[35] != '-' || traceparent[52] != '-' {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	version, ok := decodeLowerHex(traceparent[0:2])
+	if !ok || version[0] == 0xff {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	// version 00 has a fixed size, future versions may append fields
+	if (version[0] == 0x00 && len(traceparent) != size) ||
+		(version[0] != 0x00 && len(traceparent) > size && traceparent[size] != '-') {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	traceID, ok1 := decodeLowerHex(traceparent[3:35])
+	spanID, ok2 := decodeLowerHex(traceparent[36:52])
+	flags, ok3 := decodeLowerHex(traceparent[53:55])
+	if !ok1 || !ok2 || !ok3 {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	sc := SpanContext{TraceFlags: flags[0]}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+
+	if !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+
+	return sc, nil
+}
+
+// decodeLowerHex decodes a hex string, rejecting uppercase digits as required by W3C Trace Context.
+func decodeLowerHex(s string) ([]byte, bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 'A' && s[i] <= 'F' {
+			return nil, false
+		}
+	}
+
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a copy of ctx carrying the span context.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// ContextWithTraceparent parses a W3C traceparent header value and returns a copy
+// of ctx carrying the resulting span context.
+//
+// Example usage:
+//
+//	ctx, err := slogmulti.ContextWithTraceparent(r.Context(), r.Header.Get("traceparent"))
+func ContextWithTraceparent(ctx context.Context, traceparent string) (context.Context, error) {
+	sc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		return ctx, err
+	}
+
+	return ContextWithSpanContext(ctx, sc), nil
+}
+
+// SpanContextFromContext returns the span context stored in ctx, if any.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// SpanContextExtractor returns the span context of the current logging context.
+// A custom extractor can be used to bridge an existing tracing SDK.
+type SpanContextExtractor func(ctx context.Context) (SpanContext, bool)
+
+func extractSpanContext(ctx context.Context, extractors []SpanContextExtractor) (SpanContext, bool) {
+	for _, extractor := range extractors {
+		if sc, ok := extractor(ctx); ok {
+			return sc, true
+		}
+	}
+
+	return SpanContext{}, false
+}
+
+// TraceAttrs creates a middleware adding trace_id, span_id and trace_flags attributes
+// to records logged with a context carrying a span context.
+// When no extractor is provided, SpanContextFromContext is used.
+//
+// Trace attributes are added at the top level, even after WithGroup, and are
+// skipped when the record already carries a top-level attribute with the same key.
+//
+// Example usage:
+//
+//	logger := slog.New(
+//	    slogmulti.
+//	        Pipe(slogmulti.TraceAttrs()).
+//	        Handler(sink),
+//	)
+//
+//	ctx, _ := slogmulti.ContextWithTraceparent(r.Context(), r.Header.Get("traceparent"))
+//	logger.InfoContext(ctx, "hello")
+//	// level=INFO msg=hello trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 trace_flags=01
+//
+// Args:
+//
+//	extractors: Functions returning the span context of the logging context, evaluated in order
+//
+// Returns:
+//
+//	A middleware adding trace correlation attributes to records
+func TraceAttrs(extractors ...SpanContextExtractor) Middleware {
+	if len(extractors) == 0 {
+		extractors = []SpanContextExtractor{SpanContextFromContext}
+	}
+
+	return func(next slog.Handler) slog.Handler {
+		return &TraceAttrsHandler{
+			next:       next,
+			extractors: extractors,
+			root:       newRootAttrs(next),
+		}
+	}
+}
+
+// Ensure TraceAttrsHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*TraceAttrsHandler)(nil)
+
+// TraceAttrsHandler adds trace correlation attributes to records before
+// forwarding them to the next handler. See TraceAttrs.
+type TraceAttrsHandler struct {
+	// next is the handler receiving records
+	next slog.Handler
+	// extractors return the span context of the logging context, evaluated in order
+	extractors []SpanContextExtractor
+	// root adds trace attributes outside of the current groups
+	root rootAttrs
+}
+
+// Enabled checks if the next handler is enabled for the given log level.
+// This method implements the slog.Handler interface requirement.
+func (h *TraceAttrsHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle adds trace attributes to records logged with a span context and forwards them to the next handler.
+// This method implements the slog.Handler interface requirement.
+func (h *TraceAttrsHandler) Handle(ctx context.Context, r slog.Record) error {
+	sc, ok := extractSpanContext(ctx, h.extractors)
+	if !ok {
+		return h.next.Handle(ctx, r)
+	}
+
+	return h.root.handle(ctx, h.next, r, []slog.Attr{
+		slog.String("trace_id", sc.TraceIDString()),
+		slog.String("span_id", sc.SpanIDString()),
+		slog.String("trace_flags", sc.TraceFlagsString()),
+	})
+}
+
+// WithAttrs creates a new TraceAttrsHandler with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *TraceAttrsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := h.next.WithAttrs(attrs)
+
+	return &TraceAttrsHandler{
+		next:       next,
+		extractors: h.extractors,
+		root:       h.root.withAttrs(next, attrs),
+	}
+}
+
+// WithGroup creates a new TraceAttrsHandler with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *TraceAttrsHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	return &TraceAttrsHandler{
+		next:       h.next.WithGroup(name),
+		extractors: h.extractors,
+		root:       h.root.withGroup(name),
+	}
+}
+
+// Flush flushes the next handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *TraceAttrsHandler) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Close closes the next handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *TraceAttrsHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Inspect describes the TraceAttrs middleware and the next handler.
+// This method implements the Inspector interface.
+func (h *TraceAttrsHandler) Inspect() HandlerNode {
+	return inspectNode("TraceAttrs", h.root.groups, nil, h.next)
+}
+
+// TraceSampled returns a predicate that checks if the logging context carries a sampled trace.
+// When no extractor is provided, SpanContextFromContext is used.
+//
+// Example usage:
+//
+//	r := slogmulti.Router().
+//	    Add(consoleHandler).
+//	    Add(expensiveHandler, slogmulti.TraceSampled()).
+//	    Handler()
+//
+// Args:
+//
+//	extractors: Functions returning the span context of the logging context, evaluated in order
+//
+// Returns:
+//
+//	A function that checks if the logging context carries a sampled trace
+func TraceSampled(extractors ...SpanContextExtractor) func(ctx context.Context, r slog.Record) bool {
+	if len(extractors) == 0 {
+		extractors = []SpanContextExtractor{SpanContextFromContext}
+	}
+
+	return func(ctx context.Context, r slog.Record) bool {
+		sc, ok := extractSpanContext(ctx, extractors)
+		return ok && sc.IsSampled()
+	}
+}
@@ -0,0 +1,132 @@
+package slogmulti
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	is.NoError(err)
+	is.True(sc.IsValid())
+	is.True(sc.IsSampled())
+	is.Equal("4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceIDString())
+	is.Equal("00f067aa0ba902b7", sc.SpanIDString())
+	is.Equal("01", sc.TraceFlagsString())
+	is.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+
+	sc, err = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future")
+	is.NoError(err)
+	is.False(sc.IsSampled())
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceparent(invalid)
+		is.ErrorIs(err, ErrInvalidTraceparent, invalid)
+	}
+}
+
+func TestTraceAttrs(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(TraceAttrs())
+
+	ctx, err := ContextWithTraceparent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	is.NoError(err)
+
+	logger.InfoContext(ctx, "hello")
+	is.Equal(map[string]any{
+		"level":       "INFO",
+		"msg":         "hello",
+		"trace_id":    "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":     "00f067aa0ba902b7",
+		"trace_flags": "01",
+	}, decodeJSONLine(t, buf))
+
+	logger.Info("hello")
+	is.Equal(map[string]any{
+		"level": "INFO",
+		"msg":   "hello",
+	}, decodeJSONLine(t, buf))
+}
+
+func TestTraceAttrsWithGroup(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(TraceAttrs())
+
+	ctx, err := ContextWithTraceparent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	is.NoError(err)
+
+	// trace attributes stay at the top level
+	logger.With("env", "prod").WithGroup("http").With("method", "GET").InfoContext(ctx, "hello", "status", 200)
+	is.Equal(map[string]any{
+		"level":       "INFO",
+		"msg":         "hello",
+		"env":         "prod",
+		"http":        map[string]any{"method": "GET", "status": float64(200)},
+		"trace_id":    "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":     "00f067aa0ba902b7",
+		"trace_flags": "01",
+	}, decodeJSONLine(t, buf))
+
+	logger.WithGroup("http").InfoContext(ctx, "hello")
+	is.Equal(map[string]any{
+		"level":       "INFO",
+		"msg":         "hello",
+		"trace_id":    "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":     "00f067aa0ba902b7",
+		"trace_flags": "01",
+	}, decodeJSONLine(t, buf))
+
+	// existing keys are not duplicated
+	logger.With("trace_id", "custom").WithGroup("http").InfoContext(ctx, "hello")
+	is.Equal(`{"level":"INFO","msg":"hello","trace_id":"custom","span_id":"00f067aa0ba902b7","trace_flags":"01"}`+"\n", buf.String())
+	buf.Reset()
+
+	logger.InfoContext(ctx, "hello", "span_id", "custom")
+	is.Equal(`{"level":"INFO","msg":"hello","span_id":"custom","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","trace_flags":"01"}`+"\n", buf.String())
+}
+
+func TestTraceSampled(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	all := bytes.NewBufferString("")
+	sampled := bytes.NewBufferString("")
+
+	logger := slog.New(
+		Router().
+			Add(slog.NewTextHandler(all, &slog.HandlerOptions{ReplaceAttr: remoteTimeReplaceAttr})).
+			Add(slog.NewTextHandler(sampled, &slog.HandlerOptions{ReplaceAttr: remoteTimeReplaceAttr}), TraceSampled()).
+			Handler(),
+	)
+
+	sampledCtx := ContextWithSpanContext(context.Background(), SpanContext{TraceID: [16]byte{1}, SpanID: [8]byte{1}, TraceFlags: 0x01})
+	notSampledCtx := ContextWithSpanContext(context.Background(), SpanContext{TraceID: [16]byte{1}, SpanID: [8]byte{1}})
+
+	logger.InfoContext(sampledCtx, "sampled")
+	logger.InfoContext(notSampledCtx, "not sampled")
+	logger.Info("no trace")
+
+	is.Equal("level=INFO msg=sampled\nlevel=INFO msg=\"not sampled\"\nlevel=INFO msg=\"no trace\"\n", all.String())
+	is.Equal("level=INFO msg=sampled\n", sampled.String())
+}
@@ -0,0 +1,307 @@
+package slogmulti
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	slogcommon "github.com/samber/slog-common"
+	"github.com/samber/slog-multi/internal/slogattr"
+)
+
+// TransformRule rewrites a list of attributes.
+// Rules receive the full attribute tree of a record (attributes from WithAttrs
+// nested under their groups, followed by the record attributes) and must not
+// mutate the input slice.
+//
+// Attribute paths are dotted: "error.message" targets the "message" attribute
+// of the "error" group.
+type TransformRule func(attrs []slog.Attr) []slog.Attr
+
+// RenameAttr returns a rule that renames the attribute at the given path,
+// keeping it in the same group.
+//
+// Example usage:
+//
+//	slogmulti.RenameAttr("err", "error")
+//	slogmulti.RenameAttr("http.status", "status_code")
+func RenameAttr(path string, newKey string) TransformRule {
+	segments := slogattr.SplitPath(path)
+
+	return func(attrs []slog.Attr) []slog.Attr {
+		return updateAttrsByPath(attrs, segments, func(attr slog.Attr) (slog.Attr, bool) {
+			attr.Key = newKey
+			return attr, true
+		})
+	}
+}
+
+// MoveAttr returns a rule that moves the attribute at path `from` to path `to`.
+// It can be used to move an attribute into or out of a group. Missing groups are created.
+// The move is skipped when a non-group attribute holds the name of a destination group.
+//
+// Example usage:
+//
+//	slogmulti.MoveAttr("err", "error.message")
+//	slogmulti.MoveAttr("request.id", "request_id")
+func MoveAttr(from string, to string) TransformRule {
+	fromSegments := slogattr.SplitPath(from)
+	toSegments := slogattr.SplitPath(to)
+	if len(toSegments) == 0 {
+		panic("slog-multi: MoveAttr requires a destination path")
+	}
+
+	groups := toSegments[:len(toSegments)-1]
+	key := toSegments[len(toSegments)-1]
+
+	return func(attrs []slog.Attr) []slog.Attr {
+		output, removed := removeAttrsByPath(attrs, fromSegments)
+		for _, attr := range removed {
+			var ok bool
+			output, ok = setAttrByPath(output, groups, slog.Attr{Key: key, Value: attr.Value})
+			if !ok {
+				// a non-group attribute is in the way: the move is skipped
+				return attrs
+			}
+		}
+		return output
+	}
+}
+
+// DropAttr returns a rule that removes the attributes at the given paths.
+//
+// Example usage:
+//
+//	slogmulti.DropAttr("password", "user.email")
+func DropAttr(paths ...string) TransformRule {
+	segments := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		segments = append(segments, slogattr.SplitPath(path))
+	}
+
+	return func(attrs []slog.Attr) []slog.Attr {
+		for _, path := range segments {
+			attrs, _ = removeAttrsByPath(attrs, path)
+		}
+		return attrs
+	}
+}
+
+// CoerceAttr returns a rule that converts the value of the attribute at the given path.
+// The value is resolved before being passed to the callback.
+//
+// Example usage:
+//
+//	slogmulti.CoerceAttr("user.id", func(v slog.Value) slog.Value {
+//	    return slog.StringValue(v.String())
+//	})
+func CoerceAttr(path string, coerce func(slog.Value) slog.Value) TransformRule {
+	segments := slogattr.SplitPath(path)
+
+	return func(attrs []slog.Attr) []slog.Attr {
+		return updateAttrsByPath(attrs, segments, func(attr slog.Attr) (slog.Attr, bool) {
+			attr.Value = coerce(attr.Value.Resolve())
+			return attr, true
+		})
+	}
+}
+
+// DurationToMillis returns a rule that converts time.Duration attributes at the given
+// paths into an int64 number of milliseconds. Other kinds are left untouched.
+func DurationToMillis(paths ...string) TransformRule {
+	return coerceMany(paths, func(v slog.Value) slog.Value {
+		if v.Kind() == slog.KindDuration {
+			return slog.Int64Value(v.Duration().Milliseconds())
+		}
+		return v
+	})
+}
+
+// TimeToUnixMillis returns a rule that converts time.Time attributes at the given
+// paths into an int64 unix timestamp in milliseconds. Other kinds are left untouched.
+func TimeToUnixMillis(paths ...string) TransformRule {
+	return coerceMany(paths, func(v slog.Value) slog.Value {
+		if v.Kind() == slog.KindTime {
+			return slog.Int64Value(v.Time().UnixMilli())
+		}
+		return v
+	})
+}
+
+// ToString returns a rule that converts attributes at the given paths into strings.
+// Groups are left untouched.
+func ToString(paths ...string) TransformRule {
+	return coerceMany(paths, func(v slog.Value) slog.Value {
+		switch v.Kind() {
+		case slog.KindGroup:
+			return v
+		case slog.KindDuration:
+			return slog.StringValue(v.Duration().String())
+		case slog.KindTime:
+			return slog.StringValue(v.Time().Format(time.RFC3339Nano))
+		case slog.KindAny:
+			return slog.StringValue(fmt.Sprintf("%+v", v.Any()))
+		default:
+			return slog.StringValue(v.String())
+		}
+	})
+}
+
+func coerceMany(paths []string, coerce func(slog.Value) slog.Value) TransformRule {
+	rules := make([]TransformRule, 0, len(paths))
+	for _, path := range paths {
+		rules = append(rules, CoerceAttr(path, coerce))
+	}
+
+	return func(attrs []slog.Attr) []slog.Attr {
+		for _, rule := range rules {
+			attrs = rule(attrs)
+		}
+		return attrs
+	}
+}
+
+// DefaultAttr returns a rule that sets the attribute at the given path when it is missing.
+//
+// Example usage:
+//
+//	slogmulti.DefaultAttr("env", "production")
+//	slogmulti.DefaultAttr("service.name", "api")
+func DefaultAttr(path string, value any) TransformRule {
+	segments := slogattr.SplitPath(path)
+	if len(segments) == 0 {
+		panic("slog-multi: DefaultAttr requires a path")
+	}
+
+	groups := segments[:len(segments)-1]
+	attr := slog.Any(segments[len(segments)-1], value)
+
+	return func(attrs []slog.Attr) []slog.Attr {
+		if _, ok := slogattr.FindByPath(attrs, segments); ok {
+			return attrs
+		}
+		output, _ := setAttrByPath(attrs, groups, attr)
+		return output
+	}
+}
+
+// Ensure TransformHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*TransformHandler)(nil)
+
+// TransformHandler applies a list of TransformRule to every log record before
+// forwarding it to the next handler.
+//
+// Attributes and groups received via WithAttrs and WithGroup are accumulated by
+// the TransformHandler instead of being forwarded, so that rules can rename, move
+// or drop them as well. The next handler receives records with the final
+// attribute tree, groups included.
+type TransformHandler struct {
+	// next is the handler receiving transformed records
+	next slog.Handler
+	// rules are applied in order to the attribute tree of each record
+	rules []TransformRule
+	// groups tracks the current group hierarchy
+	groups []string
+	// attrs contains accumulated attributes, nested under their groups
+	attrs []slog.Attr
+}
+
+// Transform creates a middleware that rewrites attribute keys and values, using
+// declarative rules applied in order. Rules are applied both to attributes added
+// with WithAttrs and to record attributes.
+//
+// Each branch of a Fanout can have its own schema:
+//
+//	handler := slogmulti.Fanout(
+//	    slogmulti.Pipe(slogmulti.Transform(
+//	        slogmulti.RenameAttr("err", "error"),
+//	        slogmulti.DurationToMillis("latency"),
+//	    )).Handler(datadogHandler),
+//	    slogmulti.Pipe(slogmulti.Transform(
+//	        slogmulti.MoveAttr("err", "error.message"),
+//	        slogmulti.DropAttr("user.email"),
+//	        slogmulti.DefaultAttr("env", "production"),
+//	    )).Handler(lokiHandler),
+//	)
+//
+// Args:
+//
+//	rules: The transformation rules, applied in order
+//
+// Returns:
+//
+//	A middleware that applies the rules before forwarding records to the next handler
+func Transform(rules ...TransformRule) Middleware {
+	return func(next slog.Handler) slog.Handler {
+		return &TransformHandler{
+			next:   next,
+			rules:  rules,
+			groups: []string{},
+			attrs:  []slog.Attr{},
+		}
+	}
+}
+
+// Enabled checks if the next handler is enabled for the given log level.
+// This method implements the slog.Handler interface requirement.
+func (h *TransformHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle applies the transformation rules to the record and forwards it to the next handler.
+// This method implements the slog.Handler interface requirement.
+func (h *TransformHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := mergeRecordAttrs(h.attrs, h.groups, &r)
+	for _, rule := range h.rules {
+		attrs = rule(attrs)
+	}
+
+	return h.next.Handle(ctx, recordWithAttrs(r, attrs))
+}
+
+// WithAttrs creates a new TransformHandler with additional attributes.
+// This method implements the slog.Handler interface requirement.
+func (h *TransformHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TransformHandler{
+		next:   h.next,
+		rules:  h.rules,
+		groups: slices.Clone(h.groups),
+		attrs:  slogcommon.AppendAttrsToGroup(h.groups, h.attrs, attrs...),
+	}
+}
+
+// WithGroup creates a new TransformHandler with a group name.
+// This method implements the slog.Handler interface requirement.
+func (h *TransformHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	return &TransformHandler{
+		next:   h.next,
+		rules:  h.rules,
+		groups: append(slices.Clone(h.groups), name),
+		attrs:  h.attrs,
+	}
+}
+
+// Flush flushes the next handler, if it implements Flusher.
+// This method implements the Flusher interface.
+func (h *TransformHandler) Flush(ctx context.Context) error {
+	return flushHandler(ctx, h.next)
+}
+
+// Close closes the next handler, if it implements Closer.
+// This method implements the Closer interface.
+func (h *TransformHandler) Close(ctx context.Context) error {
+	return closeHandler(ctx, h.next)
+}
+
+// Inspect describes the Transform middleware, its accumulated groups and attributes, and the next handler.
+// This method implements the Inspector interface.
+func (h *TransformHandler) Inspect() HandlerNode {
+	return inspectNode("Transform", h.groups, h.attrs, h.next)
+}
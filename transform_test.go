@@ -0,0 +1,147 @@
+package slogmulti
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newJSONTestLogger(mw Middleware) (*slog.Logger, *bytes.Buffer) {
+	buf := bytes.NewBufferString("")
+	sink := slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		Level:       slog.LevelDebug,
+		ReplaceAttr: remoteTimeReplaceAttr,
+	})
+	return slog.New(Pipe(mw).Handler(sink)), buf
+}
+
+func decodeJSONLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	output := map[string]any{}
+	err := json.Unmarshal(buf.Bytes(), &output)
+	if err != nil {
+		t.Fatalf("invalid json output %q: %v", buf.String(), err)
+	}
+	buf.Reset()
+	return output
+}
+
+func TestTransform(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(Transform(
+		RenameAttr("err", "error"),
+		MoveAttr("request.id", "request_id"),
+		MoveAttr("code", "error.code"),
+		DropAttr("user.email", "password"),
+		DurationToMillis("latency"),
+		DefaultAttr("env", "production"),
+		DefaultAttr("service.name", "api"),
+	))
+
+	logger.
+		With(slog.Group("user", slog.String("id", "u-1"), slog.String("email", "a@b.c"))).
+		Info("hello",
+			slog.String("err", "boom"),
+			slog.Group("request", slog.String("id", "r-1")),
+			slog.Int("code", 42),
+			slog.String("password", "secret"),
+			slog.Duration("latency", 1500*time.Millisecond),
+			slog.String("env", "dev"),
+		)
+
+	is.Equal(map[string]any{
+		"level":      "INFO",
+		"msg":        "hello",
+		"user":       map[string]any{"id": "u-1"},
+		"error":      "boom",
+		"code":       float64(42), // "error" is not a group: the move is skipped
+		"request_id": "r-1",
+		"latency":    float64(1500),
+		"env":        "dev",
+		"service":    map[string]any{"name": "api"},
+	}, decodeJSONLine(t, buf))
+}
+
+func TestTransformWithGroup(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(Transform(
+		MoveAttr("http.status", "status"),
+		RenameAttr("http.req.path", "route"),
+		DropAttr("http.req.secret"),
+	))
+
+	logger.
+		WithGroup("http").
+		With("status", 200).
+		WithGroup("req").
+		Info("request", "path", "/", "secret", "xxx")
+
+	is.Equal(map[string]any{
+		"level":  "INFO",
+		"msg":    "request",
+		"status": float64(200),
+		"http":   map[string]any{"req": map[string]any{"route": "/"}},
+	}, decodeJSONLine(t, buf))
+
+	// empty groups are not emitted
+	logger.WithGroup("http").Info("empty")
+	is.Equal(map[string]any{
+		"level": "INFO",
+		"msg":   "empty",
+	}, decodeJSONLine(t, buf))
+}
+
+func TestTransformDoesNotMutateSharedAttrs(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(Transform(
+		RenameAttr("a", "b"),
+		ToString("n"),
+	))
+	logger = logger.With("a", 1, "n", 2)
+
+	logger.Info("first")
+	first := decodeJSONLine(t, buf)
+	logger.Info("second")
+	second := decodeJSONLine(t, buf)
+
+	is.Equal(float64(1), first["b"])
+	is.Equal("2", first["n"])
+	is.Equal(first["b"], second["b"])
+	is.Equal(first["n"], second["n"])
+}
+
+func TestTransformKeepsScalarInTheWay(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	logger, buf := newJSONTestLogger(Transform(
+		MoveAttr("code", "error.code"),
+		DefaultAttr("error.retryable", false),
+	))
+
+	logger.Info("hello", "error", "connection refused", "code", 42)
+	is.Equal(map[string]any{
+		"level": "INFO",
+		"msg":   "hello",
+		"error": "connection refused",
+		"code":  float64(42),
+	}, decodeJSONLine(t, buf))
+
+	logger.Info("hello", slog.Group("error", slog.String("message", "connection refused")), "code", 42)
+	is.Equal(map[string]any{
+		"level": "INFO",
+		"msg":   "hello",
+		"error": map[string]any{"message": "connection refused", "code": float64(42), "retryable": false},
+	}, decodeJSONLine(t, buf))
+}
@@ -0,0 +1,186 @@
+package slogmulti
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+
+	slogcommon "github.com/samber/slog-common"
+)
+
+// Ensure ConditionalHandler implements the slog.Handler interface at compile time
+var _ slog.Handler = (*ConditionalHandler)(nil)
+
+// ConditionalHandler applies a middleware only to the records matching a predicate.
+// Other records go straight to the next handler. See When and Unless.
+type ConditionalHandler struct {
+	// matched is the middleware applied to the next handler
+	matched slog.Handler
+	// next is the handler receiving unmatched records
+	next slog.Handler
+	// condition evaluates the predicate on a RecordView, building the merged record only when needed
+	condition Matcher
+	negate    bool
+
+	// groups and attrs are tracked to evaluate the predicate, like routes do
+	groups []string
+	attrs  []slog.Attr
+}
+
+// When creates a middleware applying mdw only to the records matching the
+// predicate. Records that do not match go straight to the next handler, without
+// going through mdw. Router predicates (LevelIs, AttrKindIs...) can be used.
+//
+// Like for routes, the predicate sees the attributes added with WithAttrs and
+// WithGroup. Attributes and groups are forwarded to both paths.
+//
+// Example usage:
+//
+//	logger := slog.New(
+//	    slogmulti.
+//	        Pipe(slogmulti.When(slogmulti.AttrKindIs("user", slog.KindGroup), redactPII)).
+//	        Pipe(slogmulti.When(slogmulti.LevelIs(slog.LevelError), addStackTrace)).
+//	        Handler(sink),
+//	)
+//
+// Args:
+//
+//	predicate: Selects the records going through mdw
+//	mdw: The middleware applied to matching records
+//
+// Returns:
+//
+//	A middleware applying mdw conditionally
+func When(predicate func(ctx context.Context, r slog.Record) bool, mdw Middleware) Middleware {
+	return newConditionalMiddleware(predicate, mdw, false)
+}
+
+// Unless creates a middleware applying mdw only to the records not matching
+// the predicate. See When.
+func Unless(predicate func(ctx context.Context, r slog.Record) bool, mdw Middleware) Middleware {
+	return newConditionalMiddleware(predicate, mdw, true)
+}
+
+func newConditionalMiddleware(predicate func(ctx context.Context, r slog.Record) bool, mdw Middleware, negate bool) Middleware {
+	if predicate == nil {
+		panic("slog-multi: predicate is required")
+	}
+	if mdw == nil {
+		panic("slog-multi: middleware is required")
+	}
+
+	return func(next slog.Handler) slog.Handler {
+		if next == nil {
+			panic("slog-multi: next is required")
+		}
+
+		return &ConditionalHandler{
+			matched:   mdw(next),
+			next:      next,
+			condition: Predicate(predicate),
+			negate:    negate,
+			groups:    []string{},
+			attrs:     []slog.Attr{},
+		}
+	}
+}
+
+// Enabled checks if either path is enabled for the given log level, since the
+// predicate cannot be evaluated without a record.
+// This method implements the slog.Handler interface requirement.
+func (h *ConditionalHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.matched.Enabled(ctx, l) || h.next.Enabled(ctx, l)
+}
+
+// Handle forwards the record to the middleware when the predicate matches, or
+// to the next handler otherwise.
+// This method implements the slog.Handler interface requirement.
+func (h *ConditionalHandler) Handle(ctx context.Context, r slog.Record) error {
+	handler := h.next
+	if h.match(ctx, r) {
+		handler = h.matched
+	}
+
+	if !handler.Enabled(ctx, r.Level) {
+		return nil
+	}
+
+	return handler.Handle(ctx, r)
+}
+
+func (h *ConditionalHandler) match(ctx context.Context, r slog.Record) bool {
+	return h.condition.Match(ctx, newRecordView(r, h.groups, h.attrs)) != h.negate
+}
+
+// WithAttrs forwards the attributes to both paths.
+// This method implements the slog.Handler interface requirement.
+func (h *ConditionalHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ConditionalHandler{
+		matched:   h.matched.WithAttrs(slices.Clone(attrs)),
+		next:      h.next.WithAttrs(slices.Clone(attrs)),
+		condition: h.condition,
+		negate:    h.negate,
+		groups:    h.groups,
+		attrs:     slogcommon.AppendAttrsToGroup(h.groups, h.attrs, attrs...),
+	}
+}
+
+// WithGroup forwards the group to both paths.
+// This method implements the slog.Handler interface requirement.
+func (h *ConditionalHandler) WithGroup(name string) slog.Handler {
+	// https://cs.opensource.google/go/x/exp/+/46b07846:slog/handler.go;l=247
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, 0, len(h.groups)+1)
+	groups = append(groups, h.groups...)
+	groups = append(groups, name)
+
+	return &ConditionalHandler{
+		matched:   h.matched.WithGroup(name),
+		next:      h.next.WithGroup(name),
+		condition: h.condition,
+		negate:    h.negate,
+		groups:    groups,
+		attrs:     h.attrs,
+	}
+}
+
+// Flush flushes the middleware, which is expected to flush the next handler.
+// When the middleware does not implement Flusher, the next handler is flushed.
+// This method implements the Flusher interface.
+func (h *ConditionalHandler) Flush(ctx context.Context) error {
+	switch h.matched.(type) {
+	case Flusher, interface{ Flush() error }:
+		return flushHandler(ctx, h.matched)
+	}
+
+	return flushHandler(ctx, h.next)
+}
+
+// Close closes the middleware, which is expected to close the next handler.
+// When the middleware does not implement Closer, the next handler is closed.
+// This method implements the Closer interface.
+func (h *ConditionalHandler) Close(ctx context.Context) error {
+	switch h.matched.(type) {
+	case Closer, io.Closer:
+		return closeHandler(ctx, h.matched)
+	}
+
+	return closeHandler(ctx, h.next)
+}
+
+// Inspect describes the condition and the middleware applied to matching records.
+// This method implements the Inspector interface.
+func (h *ConditionalHandler) Inspect() HandlerNode {
+	typ := "When"
+	if h.negate {
+		typ = "Unless"
+	}
+
+	node := inspectNode(typ, nil, nil, h.matched)
+	node.Route = matcherLabel(h.condition)
+	return node
+}
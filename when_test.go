@@ -0,0 +1,112 @@
+package slogmulti
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/samber/slog-multi/slogmultitest"
+	"github.com/stretchr/testify/assert"
+)
+
+// redactMiddleware replaces every attribute of the record with "***" and counts calls.
+func redactMiddleware(calls *int) Middleware {
+	return NewHandleInlineMiddleware(func(ctx context.Context, record slog.Record, next func(context.Context, slog.Record) error) error {
+		*calls++
+		output := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+		record.Attrs(func(attr slog.Attr) bool {
+			output.AddAttrs(slog.String(attr.Key, "***"))
+			return true
+		})
+		return next(ctx, output)
+	})
+}
+
+func TestWhen(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	calls := 0
+	recorder := slogmultitest.NewRecordingHandler(nil)
+	logger := slog.New(Pipe(When(AttrKindIs("user", slog.KindGroup), redactMiddleware(&calls))).Handler(recorder))
+
+	logger.Info("plain", "email", "a@b.c")
+	logger.Info("with user", slog.Group("user", slog.String("id", "42")), "email", "a@b.c")
+
+	// the predicate sees attributes added with WithAttrs
+	logger.With(slog.Group("user", slog.String("id", "43"))).Info("derived", "email", "a@b.c")
+
+	is.Equal(2, calls)
+
+	record, _ := recorder.FindByMessage("plain")
+	slogmultitest.AssertAttr(t, record, "email", "a@b.c")
+	record, _ = recorder.FindByMessage("with user")
+	slogmultitest.AssertAttr(t, record, "email", "***")
+	record, _ = recorder.FindByMessage("derived")
+	slogmultitest.AssertAttr(t, record, "email", "***")
+	slogmultitest.AssertAttr(t, record, "user.id", "43")
+}
+
+func TestUnless(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	calls := 0
+	recorder := slogmultitest.NewRecordingHandler(nil)
+	logger := slog.New(Pipe(Unless(LevelIs(slog.LevelError), redactMiddleware(&calls))).Handler(recorder))
+
+	logger.WithGroup("g").Info("info", "a", 1)
+	logger.WithGroup("g").Error("error", "a", 1)
+
+	is.Equal(1, calls)
+
+	record, _ := recorder.FindByMessage("info")
+	slogmultitest.AssertAttr(t, record, "g.a", "***")
+	record, _ = recorder.FindByMessage("error")
+	slogmultitest.AssertAttr(t, record, "g.a", 1)
+}
+
+func TestWhenEnabled(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	sink := slogmultitest.NewRecordingHandler(slog.LevelInfo)
+	drop := NewEnabledInlineMiddleware(func(ctx context.Context, level slog.Level, next func(context.Context, slog.Level) bool) bool {
+		return false
+	})
+
+	handler := When(MessageIs("secret"), drop)(sink)
+	is.True(handler.Enabled(context.Background(), slog.LevelInfo))
+	is.False(handler.Enabled(context.Background(), slog.LevelDebug))
+
+	logger := slog.New(handler)
+	logger.Info("secret")
+	logger.Info("public")
+	is.Equal([]string{"public"}, sink.Messages())
+
+	is.Panics(func() { When(nil, drop) })
+	is.Panics(func() { Unless(MessageIs("secret"), nil) })
+}
+
+func TestWhenLifecycleAndInspect(t *testing.T) {
+	t.Parallel()
+	is := assert.New(t)
+
+	sink := &lifecycleHandler{}
+	handler := Pipe(When(LevelIs(slog.LevelError), Transform())).Handler(sink)
+
+	is.NoError(Flush(context.Background(), handler))
+	is.NoError(Shutdown(context.Background(), handler))
+	is.EqualValues(1, sink.flushCount.Load())
+	is.EqualValues(1, sink.closeCount.Load())
+
+	// a middleware without lifecycle methods: the next handler is closed directly
+	sink = &lifecycleHandler{}
+	handler = Pipe(Unless(LevelIs(slog.LevelError), func(next slog.Handler) slog.Handler { return &noopHandler{} })).Handler(sink)
+	is.NoError(Shutdown(context.Background(), handler))
+	is.EqualValues(1, sink.closeCount.Load())
+
+	is.Equal(`Unless route="LevelIs()"
+└── *slogmulti.noopHandler
+`, Describe(handler))
+}